@@ -1,27 +1,46 @@
 package main
 
 import (
-	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"os"
-	"slices"
+	"os/signal"
 	"strings"
 
 	utils "github.com/jonathon-chew/go-repoflow/internal/Utils"
 	cmd "github.com/jonathon-chew/go-repoflow/internal/cli"
+	"github.com/jonathon-chew/go-repoflow/internal/config"
 	"github.com/jonathon-chew/go-repoflow/internal/git"
+	"github.com/jonathon-chew/go-repoflow/internal/todo"
 )
 
 func main() {
 
+	// --force and --stage on their own are for the default behaviour, letting it rewrite files which have unstaged changes
+	// and staging the files it rewrites, which the sync hook uses so it doesn't leave the working tree dirty
+	force, stage, defaultFlow := false, false, true
+	for _, argument := range os.Args[1:] {
+		switch argument {
+		case "--force", "-force":
+			force = true
+		case "--stage", "-stage":
+			stage = true
+		default:
+			defaultFlow = false
+		}
+	}
+
 	// Check if there are arguments have been input - if so run through the cmd module
-	if len(os.Args[1:]) >= 1 {
+	if len(os.Args[1:]) >= 1 && !defaultFlow {
 		ErrProcessingCmd := cmd.CLI(os.Args[1:])
 		if ErrProcessingCmd != nil {
 
-			// Print that there was an issue and the command passed in
-			fmt.Printf("Error parsing the command line argument, %v\n", ErrProcessingCmd)
+			// Print that there was an issue and the command passed in, unless a check already reported why it failed.
+			// It goes to stderr so it can't end up in output being redirected, such as SARIF
+			if !errors.Is(ErrProcessingCmd, cmd.ErrCheckFailed) {
+				fmt.Fprintf(os.Stderr, "Error parsing the command line argument, %v\n", ErrProcessingCmd)
+			}
 
 			// Return with a bad status code to allow this to be checked in other programmes whether it was succesfully even understood!
 			os.Exit(1)
@@ -31,9 +50,6 @@ func main() {
 		}
 	}
 	// CHECK to see if their is a git folder
-	// Initilaise the known files to ignore!
-	unwantedFiles := []string{".localized", ".DS_Store", ".gitignore"}
-	unwantedExtentions := []string{".app", ".exe", ".elf", ".md"}
 	fileList := utils.FindFilesInCurrentDirectory()
 
 	if !git.FindGitFolder() {
@@ -47,6 +63,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Load the optional settings, such as who to assign new issues to
+	settings, ErrLoadingConfig := config.Load()
+	if ErrLoadingConfig != nil {
+		fmt.Printf("[ERROR]: %s\n", ErrLoadingConfig)
+		os.Exit(1)
+	}
+
 	// Get a list of all current issues
 	listOfGithubIssues, githubErr := git.ListGithubIssues(false)
 	if githubErr != nil {
@@ -65,74 +88,71 @@ func main() {
 	CurrentNumberOfIssues := len(listOfGithubIssues)
 
 	var foundNewTODO bool = false
+	// Keep going straight away if it's a directory, or one of the known unwanted files to edit
+	var filePaths []string
 	for _, fileName := range fileList {
-
-		// Keep going straight away if it's a directory
-		if fileName.IsDir() || !strings.Contains(fileName.Name(), ".") {
+		if fileName.IsDir() || todo.Skip(fileName.Name()) {
 			continue
 		}
+		filePaths = append(filePaths, fileName.Name())
+	}
 
-		// Get the lines of the file
-		var fileLine []string
+	// Stop on Ctrl-C, but only between files so an issue is never raised without its line being updated
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-		// Set the file name
-		var filePath = fileName.Name()
+	// Read and scan the files in parallel, leaving out binary and generated files.
+	// The issues are still made one file at a time below, so the numbers given to the TODOs stay predictable
+	results, ErrScanning := todo.ScanFiles(ctx, todo.FilterScannable(filePaths), 0)
+	if ErrScanning != nil {
+		fmt.Printf("[ERROR]: %s\n", ErrScanning)
+		return
+	}
 
-		// Make sure it's not one of the known unwanted files to edit
-		if slices.Contains(unwantedFiles, filePath) {
-			continue
+	for _, result := range results {
+		if ctx.Err() != nil {
+			fmt.Println("[WARNING]: Interrupted, the remaining files were not checked")
+			break
 		}
 
+		var filePath = result.Path
+
 		// Set up variables to be used to check through eveyting that's already in place
-		var unwantedExtention bool = false
 		var updatedFile bool = false
 
-		// ignore binary files!
-		for _, extension := range unwantedExtentions {
-			if strings.Contains(filePath, extension) {
-				unwantedExtention = true
-				break
-			}
+		if result.Err != nil {
+			fmt.Printf("[WARNING]: Skipping %s: %s\n", filePath, result.Err)
+			continue
 		}
 
-		if unwantedExtention {
+		// Files without any TODOs aren't kept
+		if result.File == nil {
 			continue
 		}
 
-		// Look for to dos in the file
-		file, err := os.Open(filePath)
-		if err != nil {
-			return
+		// Get the lines of the file, keeping the line endings and permissions to write it back with
+		file := result.File
+		fileLine := file.Lines
+		foundTODOs := result.Todos
+
+		// Don't raise issues for a file that can't be written back, or the issues would be raised again next time
+		if !force && hasNewTODO(foundTODOs) {
+			unstaged, ErrCheckingFile := git.HasUnstagedChanges(filePath)
+			if ErrCheckingFile != nil {
+				fmt.Printf("[WARNING]: Skipping %s: %s\n", filePath, ErrCheckingFile)
+				continue
+			}
+			if unstaged {
+				fmt.Printf("[WARNING]: Skipping %s as it has unstaged changes, stage them or run with --force\n", filePath)
+				continue
+			}
 		}
 
-		var lineNumber int
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			lineNumber++
-			line := scanner.Text()
-
-			// This is adding a number to the start of the todo as a way to keep track and act as a guard against duplicating issues!
-			if strings.Contains(line, "TODO: ") && !strings.Contains(line, ") TODO") {
-
-				// Find the with the TODO in it
-				var replaceString string = fmt.Sprintf("(#%d) TODO", CurrentNumberOfIssues+1)
-
-				// Replace the issue with the replace string which now has a number in it
-				line = strings.Replace(line, "TODO", replaceString, 1)
-
-				// Print this to the screen
-				fmt.Printf("I would like to make a github issue for: %s\nThe title is %s\nThe body is: %s on line %d\n", strings.TrimSpace(line), strings.TrimSpace(line), fileName.Name(), lineNumber)
-
-				// Incriment the number of current issues - for the next time this needs to be used
-				CurrentNumberOfIssues += 1
-
-				// Check whether the issue already exists...
-				git.MakeGithubIssue(line, fmt.Sprintf("This is from file %s on line %d\n", fileName.Name(), lineNumber))
-
-				// Conditional if something has been updated, some actions needs to happen outside of the loop
-				updatedFile, foundNewTODO = true, true
+		for _, found := range foundTODOs {
+			line := fileLine[found.Line-1]
+			lineNumber := found.Line
 
-			} else if strings.Contains(line, "TODO: ") && strings.Contains(line, ") TODO") {
+			if found.Issue != 0 {
 				// This finds OLD TODOs
 
 				// (#22) TODO: If github issue not in the list of old todos close issue
@@ -143,26 +163,80 @@ func main() {
 				} */
 
 				// issue here being TOO powerful, when run on itself it deletes the if statements! Check for number?
+				continue
 			}
 
-			// Regardless of whether a line has changed or not, add it into the list of lines to write back in
-			fileLine = append(fileLine, line)
-		}
+			// This is adding a number to the start of the todo as a way to keep track and act as a guard against duplicating issues!
 
-		if err := scanner.Err(); err != nil {
-			fmt.Println("Error reading file: ", err)
-			return
+			// Work out who wrote the TODO before the line gets changed
+			assignees := found.Assignees
+			if len(assignees) == 0 {
+				if assignee := git.ResolveAssignee(line, filePath, lineNumber, settings); assignee != "" {
+					assignees = []string{assignee}
+				}
+			}
+
+			// Replace the issue with the replace string which now has a number in it
+			fileLine[found.Line-1] = todo.Link(line, found.Item, CurrentNumberOfIssues+1)
+
+			// The comment lines after the TODO explain it, so they go first in the body
+			issueBody := fmt.Sprintf("This is from file %s on line %d\n", filePath, lineNumber)
+			if found.Body != "" {
+				issueBody = found.Body + "\n\n" + issueBody
+			}
+
+			// Print this to the screen
+			fmt.Printf("I would like to make a github issue for: %s\nThe title is %s\nThe body is: %s\n", strings.TrimSpace(fileLine[found.Line-1]), found.Title, issueBody)
+
+			// Incriment the number of current issues - for the next time this needs to be used
+			CurrentNumberOfIssues += 1
+
+			newIssue := git.Github_Issue{
+				Title:     found.Title,
+				Body:      issueBody,
+				Assignees: assignees,
+				Label:     found.Labels,
+			}
+
+			if len(assignees) > 0 {
+				fmt.Printf("Assigning the issue to: %s\n", strings.Join(assignees, ", "))
+			}
+
+			if priorityLabel := found.PriorityLabel(); priorityLabel != "" {
+				newIssue.Label = append(newIssue.Label, priorityLabel)
+			}
+
+			// Pick the milestone which is next due after the TODO's due date
+			if !found.Due.IsZero() {
+				milestone, ErrFindingMilestone := git.FindMilestoneForDueDate(found.Due)
+				if ErrFindingMilestone != nil {
+					fmt.Printf("[WARNING]: Unable to find a milestone for %s: %s\n", found.Due.Format(todo.DueDateLayout), ErrFindingMilestone)
+				}
+				newIssue.Milestone = milestone
+			}
+
+			// Check whether the issue already exists...
+			git.CreateGithubIssue(newIssue)
+
+			// Conditional if something has been updated, some actions needs to happen outside of the loop
+			updatedFile, foundNewTODO = true, true
 		}
 
 		// Write modified content back to the file
 		if updatedFile {
 
-			// Write the result of the parsing of the file to the file again!
-			err = os.WriteFile(filePath, []byte(strings.Join(fileLine, "\n")), 0644)
-			if err != nil {
+			// Write the result of the parsing of the file to the file again, through a temporary file so it's never left half written
+			if err := file.Write(); err != nil {
 				fmt.Println("Error writing file:", err)
 				return
 			}
+
+			// Stage the linked lines, so they go in with the next commit rather than sitting in the working tree
+			if stage {
+				if err := git.StageFile(filePath); err != nil {
+					fmt.Printf("[WARNING]: Unable to stage %s: %s\n", filePath, err)
+				}
+			}
 		}
 	}
 
@@ -170,3 +244,13 @@ func main() {
 		fmt.Println("No new todo found in any file in this directory")
 	}
 }
+
+// hasNewTODO reports whether any of the TODOs still needs an issue raising
+func hasNewTODO(foundTODOs []todo.Todo) bool {
+	for _, found := range foundTODOs {
+		if found.Issue == 0 {
+			return true
+		}
+	}
+	return false
+}
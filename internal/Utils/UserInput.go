@@ -25,3 +25,18 @@ func GetUserInput(message []byte) (string, error) {
 
 	return userInput, nil
 }
+
+// StdinIsTerminal reports whether someone can answer a prompt, it's false when stdin is a pipe, a file or /dev/null such as in CI
+func StdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	if info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+
+	// /dev/null is a character device too, but can never answer
+	devNull, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(info, devNull)
+}
@@ -0,0 +1,109 @@
+package browser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrNoBrowser is returned when there isn't anything to open a URL with, such as over SSH
+var ErrNoBrowser = errors.New("no browser could be found, set $BROWSER or use --print")
+
+// Opener shows a URL to the user
+type Opener interface {
+	Open(url string) error
+}
+
+// System opens URLs with the launcher for the operating system, see Command for which one is used
+type System struct {
+	GOOS     string
+	Getenv   func(string) string
+	LookPath func(string) (string, error)
+}
+
+// Default is the System opener for the machine it's running on
+func Default() System {
+	return System{GOOS: runtime.GOOS, Getenv: os.Getenv, LookPath: exec.LookPath}
+}
+
+// Open runs the launcher and waits for it, so a terminal browser from $BROWSER can use the terminal
+func (s System) Open(url string) error {
+	name, arguments, err := s.Command(url)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(name, arguments...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("unable to open %s with %s: %w", url, name, err)
+	}
+	return nil
+}
+
+// Command works out what to run to open the URL.
+// $BROWSER is used first anywhere, as a list separated by : where %s is replaced with the URL.
+// Then macOS uses open, Windows rundll32 (or start when it's missing), WSL wslview and everything else xdg-open
+func (s System) Command(url string) (string, []string, error) {
+	separator := ":"
+	if s.GOOS == "windows" {
+		separator = ";"
+	}
+
+	for _, browser := range strings.Split(s.Getenv("BROWSER"), separator) {
+		fields := strings.Fields(browser)
+		if len(fields) == 0 {
+			continue
+		}
+		if _, err := s.LookPath(fields[0]); err != nil {
+			continue
+		}
+
+		arguments := fields[1:]
+		if strings.Contains(browser, "%s") {
+			for index := range arguments {
+				arguments[index] = strings.ReplaceAll(arguments[index], "%s", url)
+			}
+		} else {
+			arguments = append(arguments, url)
+		}
+		return fields[0], arguments, nil
+	}
+
+	var candidates [][]string
+	switch {
+	case s.GOOS == "darwin":
+		candidates = [][]string{{"open", url}}
+	case s.GOOS == "windows":
+		// rundll32 doesn't treat & in the URL as the end of a command the way cmd's start does
+		candidates = [][]string{{"rundll32", "url.dll,FileProtocolHandler", url}, {"cmd", "/c", "start", "", strings.ReplaceAll(url, "&", "^&")}}
+	case s.Getenv("WSL_DISTRO_NAME") != "" || s.Getenv("WSL_INTEROP") != "":
+		// Under WSL the browser is on the Windows side
+		candidates = [][]string{{"wslview", url}, {"xdg-open", url}}
+	default:
+		candidates = [][]string{{"xdg-open", url}}
+	}
+
+	for _, candidate := range candidates {
+		if _, err := s.LookPath(candidate[0]); err == nil {
+			return candidate[0], candidate[1:], nil
+		}
+	}
+
+	return "", nil, ErrNoBrowser
+}
+
+// Printer writes the URL instead of opening it, for SSH sessions and scripts
+type Printer struct {
+	W io.Writer
+}
+
+func (p Printer) Open(url string) error {
+	_, err := fmt.Fprintln(p.W, url)
+	return err
+}
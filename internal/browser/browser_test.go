@@ -0,0 +1,72 @@
+package browser
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	t.Log("Testing System.Command picks the launcher for each platform")
+
+	const url = "https://github.com/jonathon-chew/go-repoflow?tab=readme&x=1"
+
+	cases := []struct {
+		name      string
+		goos      string
+		env       map[string]string
+		installed []string
+		want      []string
+	}{
+		{"macOS", "darwin", nil, []string{"open"}, []string{"open", url}},
+		{"linux", "linux", nil, []string{"xdg-open"}, []string{"xdg-open", url}},
+		{"wsl", "linux", map[string]string{"WSL_DISTRO_NAME": "Ubuntu"}, []string{"wslview", "xdg-open"}, []string{"wslview", url}},
+		{"wsl without wslview", "linux", map[string]string{"WSL_DISTRO_NAME": "Ubuntu"}, []string{"xdg-open"}, []string{"xdg-open", url}},
+		{"windows", "windows", nil, []string{"rundll32", "cmd"}, []string{"rundll32", "url.dll,FileProtocolHandler", url}},
+		{"windows without rundll32", "windows", nil, []string{"cmd"}, []string{"cmd", "/c", "start", "", "https://github.com/jonathon-chew/go-repoflow?tab=readme^&x=1"}},
+		{"$BROWSER", "linux", map[string]string{"BROWSER": "missing:firefox --new-tab"}, []string{"firefox", "xdg-open"}, []string{"firefox", "--new-tab", url}},
+		{"$BROWSER with %s", "darwin", map[string]string{"BROWSER": "w3m %s"}, []string{"w3m", "open"}, []string{"w3m", url}},
+	}
+
+	for _, c := range cases {
+		system := System{
+			GOOS:   c.goos,
+			Getenv: func(key string) string { return c.env[key] },
+			LookPath: func(name string) (string, error) {
+				if slices.Contains(c.installed, name) {
+					return "/usr/bin/" + name, nil
+				}
+				return "", errors.New("not found")
+			},
+		}
+
+		name, arguments, err := system.Command(url)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+
+		if got := append([]string{name}, arguments...); !slices.Equal(got, c.want) {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+
+	// Over SSH there's often nothing to open a browser with
+	headless := System{GOOS: "linux", Getenv: func(string) string { return "" }, LookPath: func(string) (string, error) { return "", errors.New("not found") }}
+	if _, _, err := headless.Command(url); !errors.Is(err, ErrNoBrowser) {
+		t.Errorf("expected ErrNoBrowser, got %v", err)
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	t.Log("Testing Printer")
+
+	var out bytes.Buffer
+	if err := (Printer{W: &out}).Open("https://example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "https://example.com\n" {
+		t.Errorf("got %q", out.String())
+	}
+}
@@ -0,0 +1,249 @@
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jonathon-chew/go-repoflow/internal/commits"
+	"github.com/jonathon-chew/go-repoflow/internal/git"
+)
+
+// FileName is the changelog that Prepend writes to by default
+const FileName string = "CHANGELOG.md"
+
+// Unreleased is the heading used for commits that haven't been tagged yet
+const Unreleased string = "Unreleased"
+
+// fileHeader starts a new changelog, https://keepachangelog.com/en/1.1.0/
+const fileHeader string = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+var issueRefRE = regexp.MustCompile(`(^|[^\w&/])#(\d+)\b`)
+
+// section is a heading and the Conventional Commit types listed under it, in the order they're written
+type section struct {
+	Heading string
+	Types   []string
+}
+
+var sections = []section{
+	{"Features", []string{"feat"}},
+	{"Bug Fixes", []string{"fix"}},
+	{"Performance Improvements", []string{"perf"}},
+	{"Reverts", []string{"revert"}},
+	{"Documentation", []string{"docs"}},
+	{"Refactoring", []string{"refactor"}},
+	{"Build and CI", []string{"build", "ci"}},
+	{"Tests", []string{"test"}},
+}
+
+// Generate writes the Markdown for a release from the commits, newest first as git log gives them.
+// The version is used for the heading, without any v prefix, and repoURL (the repository's web page) is used
+// to link the commits and #N references; without it they are left as plain text.
+func Generate(entries []git.LogEntry, version string, date time.Time, repoURL string) string {
+	var parsed []commits.Commit
+	var contributors []string
+
+	for _, entry := range entries {
+		commit := commits.Parse(entry.Message)
+		commit.Hash, commit.Author, commit.Email = entry.Hash, entry.Author, entry.Email
+
+		// Merge commits only repeat what is already listed
+		if strings.HasPrefix(commit.Description, "Merge ") && commit.Type == "" {
+			continue
+		}
+
+		parsed = append(parsed, commit)
+		if !slices.Contains(contributors, entry.Author) {
+			contributors = append(contributors, entry.Author)
+		}
+	}
+
+	var notes strings.Builder
+
+	if version == "" || version == Unreleased {
+		notes.WriteString("## [" + Unreleased + "]\n")
+	} else {
+		notes.WriteString(fmt.Sprintf("## [%s] - %s\n", strings.TrimPrefix(version, "v"), date.Format("2006-01-02")))
+	}
+
+	var breaking []commits.Commit
+	for _, commit := range parsed {
+		if commit.Breaking {
+			breaking = append(breaking, commit)
+		}
+	}
+	writeSection(&notes, "BREAKING CHANGES", breaking, repoURL, true)
+
+	listed := map[string]bool{}
+	for _, current := range sections {
+		var matched []commits.Commit
+		for _, commit := range parsed {
+			if slices.Contains(current.Types, commit.Type) {
+				matched = append(matched, commit)
+				listed[commit.Hash] = true
+			}
+		}
+		writeSection(&notes, current.Heading, matched, repoURL, false)
+	}
+
+	var other []commits.Commit
+	for _, commit := range parsed {
+		if !listed[commit.Hash] && !commit.Breaking && commit.Type != "chore" && commit.Type != "style" {
+			other = append(other, commit)
+		}
+	}
+	writeSection(&notes, "Other Changes", other, repoURL, false)
+
+	if len(contributors) > 0 {
+		notes.WriteString("\n### Contributors\n\n")
+		for _, contributor := range contributors {
+			notes.WriteString("- " + contributor + "\n")
+		}
+	}
+
+	return notes.String()
+}
+
+func writeSection(notes *strings.Builder, heading string, sectionCommits []commits.Commit, repoURL string, breaking bool) {
+	if len(sectionCommits) == 0 {
+		return
+	}
+
+	notes.WriteString("\n### " + heading + "\n\n")
+
+	for _, commit := range sectionCommits {
+		line := "- "
+		if commit.Scope != "" {
+			line += "**" + commit.Scope + ":** "
+		}
+
+		description := commit.Description
+		if breaking {
+			// The footer explains the break better than the header when there is one
+			if footer := append(commit.Footers["BREAKING CHANGE"], commit.Footers["BREAKING-CHANGE"]...); len(footer) > 0 {
+				description = strings.ReplaceAll(footer[0], "\n", " ")
+			}
+		}
+		line += linkIssues(description, repoURL)
+
+		shortHash := commit.Hash[:min(7, len(commit.Hash))]
+		if repoURL != "" {
+			line += fmt.Sprintf(" ([%s](%s/commit/%s))", shortHash, repoURL, commit.Hash)
+		} else {
+			line += " (" + shortHash + ")"
+		}
+
+		// Issues closed or referenced in the footers
+		var references []string
+		for _, token := range []string{"Closes", "Fixes", "Resolves", "Refs"} {
+			for _, value := range commit.Footers[token] {
+				for _, match := range issueRefRE.FindAllStringSubmatch("#"+strings.TrimPrefix(value, "#"), -1) {
+					references = append(references, issueLink(match[2], repoURL))
+				}
+			}
+		}
+		if len(references) > 0 {
+			line += ", closes " + strings.Join(references, ", ")
+		}
+
+		notes.WriteString(line + "\n")
+	}
+}
+
+func issueLink(number, repoURL string) string {
+	if repoURL == "" {
+		return "#" + number
+	}
+	return fmt.Sprintf("[#%s](%s/issues/%s)", number, repoURL, number)
+}
+
+// IssueRefs returns the issue numbers written as #N in the text, each once in the order they first appear
+func IssueRefs(text string) []int {
+	var numbers []int
+	for _, match := range issueRefRE.FindAllStringSubmatch(text, -1) {
+		number, err := strconv.Atoi(match[2])
+		if err != nil || slices.Contains(numbers, number) {
+			continue
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers
+}
+
+// linkIssues turns the #N references in the text into links to the issue or pull request
+func linkIssues(text, repoURL string) string {
+	if repoURL == "" {
+		return text
+	}
+	return issueRefRE.ReplaceAllStringFunc(text, func(match string) string {
+		parts := issueRefRE.FindStringSubmatch(match)
+		return parts[1] + issueLink(parts[2], repoURL)
+	})
+}
+
+// Prepend adds the release notes to the top of the changelog, under its title, creating the file if needed.
+// A release with the same heading is replaced, so running it again doesn't add the release twice.
+func Prepend(path, notes string) error {
+	mode := os.FileMode(0644)
+
+	contents, ErrReadingFile := os.ReadFile(path)
+	switch {
+	case errors.Is(ErrReadingFile, os.ErrNotExist):
+		contents = []byte(fileHeader)
+	case ErrReadingFile != nil:
+		return ErrReadingFile
+	default:
+		if info, ErrStat := os.Stat(path); ErrStat == nil {
+			mode = info.Mode().Perm()
+		}
+	}
+
+	heading, _, _ := strings.Cut(notes, "\n")
+	lines := strings.Split(string(contents), "\n")
+
+	// Remove the release if it's already there
+	if start := slices.Index(lines, heading); start >= 0 {
+		end := start + 1
+		for end < len(lines) && !strings.HasPrefix(lines[end], "## ") {
+			end++
+		}
+		lines = slices.Delete(lines, start, end)
+	}
+
+	// The newest release goes above the first release, below the title and introduction
+	insertAt := slices.IndexFunc(lines, func(line string) bool { return strings.HasPrefix(line, "## ") })
+	if insertAt < 0 {
+		insertAt = len(lines)
+		for insertAt > 0 && strings.TrimSpace(lines[insertAt-1]) == "" {
+			insertAt--
+		}
+		lines = append(lines[:insertAt], "")
+		insertAt++
+	}
+
+	// A new version replaces the unreleased notes, which it now covers
+	if heading != "## ["+Unreleased+"]" && insertAt < len(lines) && lines[insertAt] == "## ["+Unreleased+"]" {
+		end := insertAt + 1
+		for end < len(lines) && !strings.HasPrefix(lines[end], "## ") {
+			end++
+		}
+		lines = slices.Delete(lines, insertAt, end)
+	}
+
+	notesLines := strings.Split(strings.TrimRight(notes, "\n")+"\n", "\n")
+	lines = slices.Insert(lines, insertAt, notesLines...)
+
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), mode)
+}
@@ -0,0 +1,71 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonathon-chew/go-repoflow/internal/git"
+)
+
+var testEntries = []git.LogEntry{
+	{Hash: "aaaaaaaaaaaa", Author: "Alice", Message: "feat(cli): add the changelog command (#4)\n\nCloses #3"},
+	{Hash: "bbbbbbbbbbbb", Author: "Bob", Message: "fix: stop the crash"},
+	{Hash: "cccccccccccc", Author: "Alice", Message: "chore: tidy up"},
+	{Hash: "dddddddddddd", Author: "Carol", Message: "refactor!: rename the flags\n\nBREAKING CHANGE: --tags is now tags list"},
+	{Hash: "eeeeeeeeeeee", Author: "Bob", Message: "Update README.md"},
+}
+
+func TestGenerate(t *testing.T) {
+	t.Log("Testing Generate groups the commits and links them")
+
+	notes := Generate(testEntries, "v1.5.0", time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), "https://github.com/owner/repo")
+
+	for _, want := range []string{
+		"## [1.5.0] - 2026-10-19\n",
+		"### BREAKING CHANGES\n\n- --tags is now tags list ([ddddddd](https://github.com/owner/repo/commit/dddddddddddd))\n",
+		"### Features\n\n- **cli:** add the changelog command ([#4](https://github.com/owner/repo/issues/4)) ([aaaaaaa](https://github.com/owner/repo/commit/aaaaaaaaaaaa)), closes [#3](https://github.com/owner/repo/issues/3)\n",
+		"### Bug Fixes\n\n- stop the crash",
+		"### Refactoring\n\n- rename the flags",
+		"### Other Changes\n\n- Update README.md (",
+		"### Contributors\n\n- Alice\n- Bob\n- Carol\n",
+	} {
+		if !strings.Contains(notes, want) {
+			t.Errorf("expected the notes to contain %q, got:\n%s", want, notes)
+		}
+	}
+
+	if strings.Contains(notes, "tidy up") {
+		t.Errorf("chores should be left out, got:\n%s", notes)
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	t.Log("Testing Prepend keeps the newest release first and can be run again")
+
+	path := filepath.Join(t.TempDir(), FileName)
+
+	first := Generate(testEntries[1:2], "v1.0.0", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), "")
+	second := Generate(testEntries[:1], "v1.1.0", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), "")
+
+	for _, notes := range []string{first, second, second} {
+		if err := Prepend(path, notes); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	contents, _ := os.ReadFile(path)
+	text := string(contents)
+
+	if !strings.HasPrefix(text, "# Changelog\n") {
+		t.Errorf("expected the Keep a Changelog title, got:\n%s", text)
+	}
+	if strings.Count(text, "## [1.1.0]") != 1 {
+		t.Errorf("expected 1.1.0 once, got:\n%s", text)
+	}
+	if strings.Index(text, "## [1.1.0]") > strings.Index(text, "## [1.0.0]") {
+		t.Errorf("expected 1.1.0 above 1.0.0, got:\n%s", text)
+	}
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import "testing"
+
+func TestEmptyArguments(t *testing.T) {
+	t.Log("Testing empty arguments are reported rather than panicking")
+
+	// Outside of a repository every command stops with an error once the arguments have been read
+	t.Chdir(t.TempDir())
+
+	commands := map[string]func([]string) error{
+		"changelog --write": func(arguments []string) error { return changelogCommand(append([]string{"--write"}, arguments...)) },
+		"release":           releaseCommand,
+		"tag verify":        tagVerifyCommand,
+	}
+
+	for name, command := range commands {
+		if err := command([]string{""}); err == nil {
+			t.Errorf("%s with an empty argument should have failed", name)
+		}
+	}
+}
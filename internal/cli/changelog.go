@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+	"github.com/jonathon-chew/go-repoflow/internal/changelog"
+	"github.com/jonathon-chew/go-repoflow/internal/git"
+)
+
+// changelogCommand prints the release notes between two refs, or prepends them to the changelog with --write
+func changelogCommand(arguments []string) error {
+	var from, to, version, writePath string
+	var write bool
+
+	for index := 0; index < len(arguments); index++ {
+		switch arguments[index] {
+		case "--from", "-from":
+			if index+1 >= len(arguments) {
+				return fmt.Errorf("%s needs a tag after it", arguments[index])
+			}
+			index++
+			from = arguments[index]
+		case "--to", "-to":
+			if index+1 >= len(arguments) {
+				return fmt.Errorf("%s needs a tag or ref after it", arguments[index])
+			}
+			index++
+			to = arguments[index]
+		case "--version", "-version":
+			if index+1 >= len(arguments) {
+				return fmt.Errorf("%s needs a version after it", arguments[index])
+			}
+			index++
+			version = arguments[index]
+		case "--write", "-write", "-w":
+			write = true
+			// The path is optional
+			if index+1 < len(arguments) && !strings.HasPrefix(arguments[index+1], "-") {
+				index++
+				writePath = arguments[index]
+			}
+		default:
+			return fmt.Errorf("%s is not recognised by the changelog command", arguments[index])
+		}
+	}
+
+	if from == "" {
+		latestTag, ErrGetLatestTag := git.GetLatestTag()
+		if ErrGetLatestTag != nil {
+			return ErrGetLatestTag
+		}
+		from = latestTag
+	}
+
+	// Notes up to a tag are for that version, anything else hasn't been released yet
+	if version == "" && to != "" {
+		version = to
+	}
+
+	notes, ErrGenerating := releaseNotes(from, to, version)
+	if ErrGenerating != nil {
+		return ErrGenerating
+	}
+
+	if !write {
+		fmt.Print(notes)
+		return nil
+	}
+
+	if writePath == "" {
+		writePath = changelog.FileName
+	}
+
+	if ErrWriting := changelog.Prepend(writePath, notes); ErrWriting != nil {
+		return ErrWriting
+	}
+
+	aphrodite.PrintInfo(fmt.Sprintf("Added the release notes to %s\n", writePath))
+	return nil
+}
+
+// releaseNotes generates the notes for the commits between the refs which touched the paths, linking to the remote when there is one
+func releaseNotes(from, to, version string, paths ...string) (string, error) {
+	entries, ErrGettingCommits := git.CommitsBetween(from, to, paths...)
+	if ErrGettingCommits != nil {
+		return "", ErrGettingCommits
+	}
+
+	// Without a remote the notes are still useful, just without links
+	repoURL, ErrGettingRemote := git.GetRemoteWebURL()
+	if ErrGettingRemote != nil {
+		repoURL = ""
+	}
+
+	date := time.Now()
+	if to != "" {
+		if tagDate, ErrGettingDate := git.TagDate(to); ErrGettingDate == nil {
+			date = tagDate
+		}
+	}
+
+	return changelog.Generate(entries, version, date, repoURL), nil
+}
+
+// changelogMessage is the tag message for --changelog, the notes from the previous tag up to the new one for the module
+func changelogMessage(module git.Module) func(previousTag, newTag string) (string, error) {
+	return func(previousTag, newTag string) (string, error) {
+		if module.Path == "" {
+			return releaseNotes(previousTag, "", newTag)
+		}
+		return releaseNotes(previousTag, "", newTag, module.Path)
+	}
+}
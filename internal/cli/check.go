@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+	utils "github.com/jonathon-chew/go-repoflow/internal/Utils"
+	"github.com/jonathon-chew/go-repoflow/internal/git"
+)
+
+// checkCommand shows the status of every repository one folder down from the current directory
+func checkCommand(arguments []string) error {
+	var asJSON bool
+	for _, argument := range arguments {
+		switch argument {
+		case "--json", "-json":
+			asJSON = true
+		default:
+			return fmt.Errorf("%s is not recognised by --check", argument)
+		}
+	}
+
+	repos := git.CheckRepos(utils.MakeDirectoryList(utils.FindFilesInCurrentDirectory()))
+
+	if asJSON {
+		if repos == nil {
+			repos = []git.RepoStatus{}
+		}
+		return printJSON(repos)
+	}
+
+	if len(repos) == 0 {
+		fmt.Println("No git repositories found in the folders here")
+		return nil
+	}
+
+	now := time.Now()
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "REPO\tBRANCH\tAHEAD\tBEHIND\tSTAGED\tMODIFIED\tUNTRACKED\tSTASHES\tLAST COMMIT")
+
+	var failed []git.RepoStatus
+	for _, repo := range repos {
+		if repo.Error != "" {
+			failed = append(failed, repo)
+			continue
+		}
+
+		ahead, behind := "-", "-"
+		if repo.Upstream != "" {
+			ahead, behind = strconv.Itoa(repo.Ahead), strconv.Itoa(repo.Behind)
+		}
+
+		modified := strconv.Itoa(repo.Modified)
+		if repo.Conflicted > 0 {
+			modified += fmt.Sprintf(" (%d conflicted)", repo.Conflicted)
+		}
+
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%d\t%s\t%d\t%d\t%s\n", repo.Name, repo.Branch, ahead, behind, repo.Staged, modified, repo.Untracked, repo.Stashes, commitAge(now, repo.LastCommit))
+	}
+	writer.Flush()
+
+	for _, repo := range failed {
+		aphrodite.PrintWarning(fmt.Sprintf("Unable to check %s: %s\n", repo.Name, repo.Error))
+	}
+
+	return nil
+}
+
+// commitAge is how long ago the commit was in the largest unit that fits, such as 3d ago
+func commitAge(now, when time.Time) string {
+	if when.IsZero() {
+		return "no commits"
+	}
+
+	age := now.Sub(when)
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	case age < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	case age < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo ago", int(age.Hours()/24/30))
+	}
+	return fmt.Sprintf("%dy ago", int(age.Hours()/24/365))
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCommitAge(t *testing.T) {
+	t.Log("Testing commitAge")
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		age  time.Duration
+		want string
+	}{
+		{30 * time.Second, "just now"},
+		{45 * time.Minute, "45m ago"},
+		{5 * time.Hour, "5h ago"},
+		{3 * 24 * time.Hour, "3d ago"},
+		{65 * 24 * time.Hour, "2mo ago"},
+		{800 * 24 * time.Hour, "2y ago"},
+	}
+
+	for _, c := range cases {
+		if got := commitAge(now, now.Add(-c.age)); got != c.want {
+			t.Errorf("commitAge(%s) = %q, want %q", c.age, got, c.want)
+		}
+	}
+
+	if got := commitAge(now, time.Time{}); got != "no commits" {
+		t.Errorf("got %q for a repository without commits", got)
+	}
+}
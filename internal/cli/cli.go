@@ -5,11 +5,11 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
 	aphrodite "github.com/jonathon-chew/Aphrodite"
-	utils "github.com/jonathon-chew/go-repoflow/internal/Utils"
 	"github.com/jonathon-chew/go-repoflow/internal/git"
 )
 
@@ -21,9 +21,33 @@ func CLI(CommandLineArguments []string) error {
 	for index, command := range CommandLineArguments {
 		switch command {
 		default:
-			if command != "minor" && command != "major" && command != "patch"{
+			if !slices.Contains(git.BumpTypes, command) {
 				aphrodite.PrintError(command + " is not recognised")
 			}
+		case "todos":
+			return todosCommand(CommandLineArguments[index+1:])
+
+		case "hooks":
+			return hooksCommand(CommandLineArguments[index+1:])
+
+		case "tag":
+			return tagCommand(CommandLineArguments[index+1:])
+
+		case "open":
+			return openCommand(CommandLineArguments[index+1:])
+
+		case "tags":
+			return tagsCommand(CommandLineArguments[index+1:])
+
+		case "changelog":
+			return changelogCommand(CommandLineArguments[index+1:])
+
+		case "release":
+			return releaseCommand(CommandLineArguments[index+1:])
+
+		case "pr":
+			return prCommand(CommandLineArguments[index+1:])
+
 		case "--repo-stats", "-rs":
 			RepoStats, ErrGettingRepoStats := git.GetRepoStats()
 			if ErrGettingRepoStats != nil {
@@ -42,14 +66,7 @@ func CLI(CommandLineArguments []string) error {
 			return nil
 
 		case "--check", "-c":
-			entries := utils.MakeDirectoryList(utils.FindFilesInCurrentDirectory())
-
-			for _, entry := range entries {
-				ErrCheckingForUpdate := git.CheckForGitUpdate(entry)
-				if ErrCheckingForUpdate != nil {
-					return ErrCheckingForUpdate
-				}
-			}
+			return checkCommand(CommandLineArguments[index+1:])
 
 		case "--clone", "-cl":
 			git.CloneAllPublicRepos()
@@ -123,12 +140,21 @@ func CLI(CommandLineArguments []string) error {
 			return nil
 
 		case "--version", "-version", "-v":
-			fmt.Printf("v0.7.7\n")
+			verbose := slices.Contains(CommandLineArguments, "--verbose") || slices.Contains(CommandLineArguments, "-verbose")
+			printVersion(verbose)
+			return nil
 
 		case "--help", "-help", "-h":
 
 			aphrodite.PrintBold("Cyan", "No Arguments\n")
-			aphrodite.PrintColour("Green", "You can run with no arguments to check all the files in the current directory for any undocumented todos and upload them to github\n\n")
+			aphrodite.PrintColour("Green", "You can run with no arguments to check all the files in the current directory for any undocumented todos and upload them to github. Files with unstaged changes are skipped unless --force is given, and --stage stages the files it rewrites\n\n")
+
+			aphrodite.PrintBold("Cyan", "Todos\n")
+			aphrodite.PrintColour("Green", "List every TODO and FIXME in the tree with the file, line, linked issue, author and age, without needing a remote or token. Group them with --group-by directory|author and change the output with --format markdown|json|csv\n")
+			aphrodite.PrintColour("Green", "todos check --base <ref> fails when lines added since the base have a TODO without an (#N) issue reference, use --format text|github|sarif for CI\n\n")
+
+			aphrodite.PrintBold("Cyan", "Hooks\n")
+			aphrodite.PrintColour("Green", "hooks install|uninstall|status manages a pre-commit hook that checks staged TODOs, add --sync post-commit|pre-push to also raise issues from a hook. Existing hooks are kept and run first, and core.hooksPath is respected\n\n")
 
 			aphrodite.PrintBold("Cyan", "Get issues\n")
 			aphrodite.PrintColour("Green", "You can pass in a get flag which will List the github issues, this can be supplimented with --open and --closed to filter to show only issues with those flags\n\n")
@@ -137,13 +163,33 @@ func CLI(CommandLineArguments []string) error {
 			aphrodite.PrintColour("Green", "If you pass in the set flag, please pass in the title flag and body flag (in that order) to make a new issue with the relevent Title and Body\n\n")
 
 			aphrodite.PrintBold("Cyan", "Version\n")
-			aphrodite.PrintColour("Green", "Version Number can be passed in with the version flag\n\n")
+			aphrodite.PrintColour("Green", "Version Number can be passed in with the version flag, add --verbose for the commit, build date, Go version and which backends are set up to put in bug reports\n\n")
 
 			aphrodite.PrintBold("cyan", "Tags\n")
-			aphrodite.PrintColour("Green", "Returns the latest tag following the format v[number].[number].[number]\n\n")
+			aphrodite.PrintColour("Green", "Returns the latest tag by semantic version precedence, such as v1.2.3, 1.2.3 or v1.2.3-rc.1 (build metadata like +build5 is ignored)\n")
+			aphrodite.PrintColour("Green", "tags list shows every version tag newest first, with its date, tagger, commit, the commits since the tag before it and whether it's pushed. Filter with --since <date|tag>, --prerelease, --no-prerelease, --module and --limit, or print --json. Tags which aren't versions are listed at the end\n")
+			aphrodite.PrintColour("Green", "In a monorepo it lists the latest tag of each module, such as services/api/v1.3.0. Modules are the folders with their own go.mod, or set in modules in .repoflow.json\n\n")
 
 			aphrodite.PrintBold("cyan", "Increment Tag\n")
-			aphrodite.PrintColour("Green", "Finds the biggest version number in the format format v[number].[number].[number] and adds 1 to the major / minor / patch numbers\n\n")
+			aphrodite.PrintColour("Green", "Finds the latest semantic version tag and adds 1 to the major / minor / patch numbers, keeping the v prefix if the tag has one\n")
+			aphrodite.PrintColour("Green", "prerelease --preid rc makes the next release candidate (v1.4.0 to v1.5.0-rc.1, then v1.5.0-rc.2), release promotes it to v1.5.0, and --preid with major / minor / patch starts a pre-release of that version\n")
+			aphrodite.PrintColour("Green", "--yes or --push pushes the new tag without asking, --no-push never pushes it, --remote picks where it goes (origin by default) and --dry-run shows what would happen. Without a terminal it fails rather than waiting for an answer\n")
+			aphrodite.PrintColour("Green", "--module services/api bumps only that module's tags, and --if-changed skips it when nothing under its folder changed since its latest tag\n")
+			aphrodite.PrintColour("Green", "--sign makes a signed tag with your git config's signing key, GPG or SSH with gpg.format. tag verify [tags...] checks the signature of the latest tag, or every release tag with --all, and fails if any aren't signed and valid\n")
+			aphrodite.PrintColour("Green", "--bump-files writes the new version into package.json, Cargo.toml, VERSION and the version_files in .repoflow.json, commits them as chore(release): vX.Y.Z and tags that commit, undoing it all if a step fails\n\n")
+
+			aphrodite.PrintBold("cyan", "Changelog\n")
+			aphrodite.PrintColour("Green", "changelog prints Markdown release notes from the latest tag to HEAD grouped by Conventional Commit type, change the range with --from and --to. --write prepends them to CHANGELOG.md in the Keep a Changelog layout, and --changelog on --increment-tag or tag --auto uses them as the tag message\n\n")
+
+			aphrodite.PrintBold("cyan", "Release\n")
+			aphrodite.PrintColour("Green", "release [--tag v1.5.0] [--title t] [--notes-file f] [--remote r] [assets...] creates or updates the GitHub Release for the latest tag, with notes generated since the tag before it. It stops unless the tag has been pushed to --remote, origin by default. Pre-release tags are marked as pre-releases, and the assets are uploaded with a SHA256SUMS file, skipping any that haven't changed\n\n")
+
+			aphrodite.PrintBold("cyan", "Pull Requests\n")
+			aphrodite.PrintColour("Green", "pr create pushes the current branch if the remote doesn't have it and opens a pull request into the remote's default branch, or --base. The title and body come from the commits and the repository's pull_request_template.md unless --title or --body are given, issues the commits mention get Closes #N lines, leaving out merge commits, and --draft, --reviewer and --label are passed on. Without GH_PERSONAL_TOKEN the compare page is opened instead\n")
+			aphrodite.PrintColour("Green", "pr list shows the open pull requests with their review state and checks, pr view [N] shows one (the current branch's by default) with its diff stats, pr checkout N checks out its head, forks included, and pr status lists the current branch's, yours and those waiting for your review. list, view and status take --json\n\n")
+
+			aphrodite.PrintBold("cyan", "Tag Auto\n")
+			aphrodite.PrintColour("Green", "tag --auto reads the Conventional Commits since the latest tag (feat, fix, feat! and BREAKING CHANGE footers), prints which commits need which bump and makes the tag. Before 1.0.0 breaking changes bump the minor version unless pre_1_0_breaking_bump is major in .repoflow.json\n\n")
 
 			aphrodite.PrintBold("cyan", "Open Issues\n")
 			aphrodite.PrintColour("Green", "Open the github page on the issues page to manage from there\n\n")
@@ -151,8 +197,14 @@ func CLI(CommandLineArguments []string) error {
 			aphrodite.PrintBold("cyan", "Open Pull\n")
 			aphrodite.PrintColour("Green", "Open the github page on the pull request page to manage from there\n\n")
 
+			aphrodite.PrintBold("cyan", "Open\n")
+			aphrodite.PrintColour("Green", "open path/to/file.go:10-20 opens the file at the current commit with the lines highlighted. open --commit [sha], --branch [name], --blame file[:lines], --issue N and --actions open those pages, linking the way GitHub, GitLab, Bitbucket or Gitea expect. Set forge in .repoflow.json for a self-hosted site\n\n")
+
+			aphrodite.PrintBold("cyan", "Print\n")
+			aphrodite.PrintColour("Green", "Add --print to the open flags to print the page instead of opening it, for SSH sessions. The browser is $BROWSER, then open on macOS, rundll32 on Windows, wslview under WSL or xdg-open, and the page is printed when none of them are there\n\n")
+
 			aphrodite.PrintBold("cyan", "Check\n")
-			aphrodite.PrintColour("Green", "Check all folders 1 level deep to see if there are any updates required to push/pull\n\n")
+			aphrodite.PrintColour("Green", "Check all folders 1 level deep at the same time and show a table of each repository's branch, commits ahead of and behind its upstream, staged, modified and untracked files, stashes and the age of the last commit. Folders which aren't repositories are skipped, and --json prints it for scripts\n\n")
 
 			aphrodite.PrintBold("cyan", "Commit Calendar\n")
 			aphrodite.PrintColour("Green", "Print to the terminal the git history activity for the last year!\n\n")
@@ -161,38 +213,59 @@ func CLI(CommandLineArguments []string) error {
 			aphrodite.PrintColour("Green", "Clone all public repos into a temporary directory\n\n")
 
 		case "--tags", "-tags", "-t", "--tag", "-tag":
-			version, ErrGetLatestTag := git.GetLatestTag()
-			if ErrGetLatestTag != nil {
-				return ErrGetLatestTag
-			}
-			fmt.Println(version)
+			return latestTags()
 
 		case "--increment-tag", "-increment-tag", "-i", "--incrementtag", "-incrementtag":
 			var argument string
-			if len(CommandLineArguments) > index+1 {
-				argument = CommandLineArguments[index+1]
-			} else {
-				argument = ""
+			var options git.TagOptions
+			var bumpFiles bool
+
+			for extraIndex := index + 1; extraIndex < len(CommandLineArguments); extraIndex++ {
+				next, ok, ErrParsingFlag := tagFlag(CommandLineArguments, extraIndex, &options, &bumpFiles)
+				if ErrParsingFlag != nil {
+					return ErrParsingFlag
+				}
+				if ok {
+					extraIndex = next
+					continue
+				}
+
+				extraCommand := CommandLineArguments[extraIndex]
+				if !slices.Contains(git.BumpTypes, extraCommand) {
+					return fmt.Errorf("%s is not recognised by the increment tag command", extraCommand)
+				}
+				argument = extraCommand
+			}
+
+			if bumpFiles {
+				if ErrFindingFiles := addVersionFiles(&options); ErrFindingFiles != nil {
+					return ErrFindingFiles
+				}
 			}
-			ErrMakingNewTag := git.NewGitTag(argument)
+
+			ErrMakingNewTag := git.NewGitTag(argument, options)
 			if ErrMakingNewTag != nil {
 				return ErrMakingNewTag
 			}
+			return nil
+
+		case "--print", "-print":
+			// Read by the open flags, to print the page instead of opening it
 
 		case "--open", "-open", "-o":
-			ErrOpeningRemoteOrigin := git.OpenRemoteOrigin("")
+			ErrOpeningRemoteOrigin := openRemote("", CommandLineArguments)
 			if ErrOpeningRemoteOrigin != nil {
 				return ErrOpeningRemoteOrigin
 			}
 
 		case "--open-issues", "-open-issues", "-oi":
-			ErrOpeningRemoteOrigin := git.OpenRemoteOrigin("issues")
+			ErrOpeningRemoteOrigin := openRemote("issues", CommandLineArguments)
 			if ErrOpeningRemoteOrigin != nil {
 				return ErrOpeningRemoteOrigin
 			}
 
 		case "--open-pull", "-open-pull", "-op":
-			ErrOpeningRemoteOrigin := git.OpenRemoteOrigin("pull")
+			ErrOpeningRemoteOrigin := openRemote("pull", CommandLineArguments)
 			if ErrOpeningRemoteOrigin != nil {
 				return ErrOpeningRemoteOrigin
 			}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+	"github.com/jonathon-chew/go-repoflow/internal/git"
+)
+
+// hookCheckCommand is what the pre-commit hook runs, checking only the staged content
+const hookCheckCommand string = "repoflow todos check --staged"
+
+// hookSyncCommand is what the sync hook runs, the default flow which raises issues for new TODOs.
+// It stages the lines it links to the issues, so they are committed next rather than left as unstaged changes
+const hookSyncCommand string = "repoflow --stage"
+
+// hooksCommand installs, removes or reports on the git hooks that keep TODOs in sync
+func hooksCommand(arguments []string) error {
+	if len(arguments) == 0 {
+		return fmt.Errorf("hooks needs one of install, uninstall or status after it")
+	}
+
+	switch arguments[0] {
+	case "install":
+		var syncHook string
+
+		for index := 1; index < len(arguments); index++ {
+			switch arguments[index] {
+			case "--sync", "-sync":
+				if index+1 >= len(arguments) || (arguments[index+1] != "post-commit" && arguments[index+1] != "pre-push") {
+					return fmt.Errorf("%s needs post-commit or pre-push after it", arguments[index])
+				}
+				index++
+				syncHook = arguments[index]
+			default:
+				return fmt.Errorf("%s is not recognised by the hooks install command", arguments[index])
+			}
+		}
+
+		if ErrInstalling := git.InstallHook("pre-commit", hookCheckCommand); ErrInstalling != nil {
+			return ErrInstalling
+		}
+		aphrodite.PrintInfo("Installed the pre-commit hook to check staged TODOs\n")
+
+		if syncHook != "" {
+			if ErrInstalling := git.InstallHook(syncHook, hookSyncCommand); ErrInstalling != nil {
+				return ErrInstalling
+			}
+			aphrodite.PrintInfo(fmt.Sprintf("Installed the %s hook to sync TODOs with issues\n", syncHook))
+		}
+
+	case "uninstall":
+		for _, name := range git.HookNames {
+			removed, ErrUninstalling := git.UninstallHook(name)
+			if ErrUninstalling != nil {
+				return ErrUninstalling
+			}
+			if removed {
+				aphrodite.PrintInfo(fmt.Sprintf("Removed the %s hook\n", name))
+			}
+		}
+
+	case "status":
+		statuses, ErrGettingStatus := git.GetHookStatus()
+		if ErrGettingStatus != nil {
+			return ErrGettingStatus
+		}
+
+		for _, status := range statuses {
+			switch {
+			case status.Installed && status.Chained:
+				fmt.Printf("%s: installed, running the existing hook first (%s)\n", status.Name, status.Path)
+			case status.Installed:
+				fmt.Printf("%s: installed (%s)\n", status.Name, status.Path)
+			case status.Other:
+				fmt.Printf("%s: another hook is installed (%s)\n", status.Name, status.Path)
+			default:
+				fmt.Printf("%s: not installed\n", status.Name)
+			}
+		}
+
+	default:
+		return fmt.Errorf("%s is not recognised by the hooks command, use install, uninstall or status", arguments[0])
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jonathon-chew/go-repoflow/internal/browser"
+	"github.com/jonathon-chew/go-repoflow/internal/config"
+	"github.com/jonathon-chew/go-repoflow/internal/git"
+)
+
+// opener prints URLs with --print, or else opens them in the browser, printing them when there isn't one such as over SSH
+func opener(arguments []string) browser.Opener {
+	if slices.Contains(arguments, "--print") || slices.Contains(arguments, "-print") {
+		return browser.Printer{W: os.Stdout}
+	}
+	return printWithoutBrowser{browser.Default()}
+}
+
+// printWithoutBrowser prints the URL when the opener has nothing to open it with
+type printWithoutBrowser struct {
+	browser.Opener
+}
+
+func (p printWithoutBrowser) Open(url string) error {
+	err := p.Opener.Open(url)
+	if errors.Is(err, browser.ErrNoBrowser) {
+		fmt.Fprintf(os.Stderr, "[WARNING]: %s\n", err)
+		fmt.Println(url)
+		return nil
+	}
+	return err
+}
+
+// openRemote opens the remote's page, or its issues or pull requests, for the --open flags
+func openRemote(place string, arguments []string) error {
+	settings, ErrLoadingConfig := config.Load()
+	if ErrLoadingConfig != nil {
+		return ErrLoadingConfig
+	}
+
+	return git.OpenRemoteOrigin(place, settings.Forge, opener(arguments))
+}
+
+// openCommand opens a file at the current commit, or with the flags a commit, branch, blame view, issue or the CI runs
+func openCommand(arguments []string) error {
+	settings, ErrLoadingConfig := config.Load()
+	if ErrLoadingConfig != nil {
+		return ErrLoadingConfig
+	}
+
+	pages, ErrGettingPages := git.RemotePages(settings.Forge)
+	if ErrGettingPages != nil {
+		return ErrGettingPages
+	}
+
+	// The value after a flag, or the fallback when it's left off
+	optional := func(index int, fallback string) (string, int) {
+		if index+1 < len(arguments) && !strings.HasPrefix(arguments[index+1], "-") {
+			return arguments[index+1], index + 1
+		}
+		return fallback, index
+	}
+
+	var url string
+	var ErrBuilding error
+
+	for index := 0; index < len(arguments) && ErrBuilding == nil; index++ {
+		var value string
+
+		switch arguments[index] {
+		case "--print", "-print":
+			continue
+		case "--commit", "-commit":
+			value, index = optional(index, "HEAD")
+			var commit string
+			commit, ErrBuilding = git.ResolveCommit(value)
+			url = pages.Commit(commit)
+		case "--branch", "-branch":
+			value, index = optional(index, "")
+			if value == "" {
+				value, ErrBuilding = git.CurrentBranch()
+			}
+			url = pages.Branch(value)
+		case "--blame", "-blame":
+			if index+1 >= len(arguments) {
+				return fmt.Errorf("%s needs a file after it", arguments[index])
+			}
+			index++
+			url, ErrBuilding = fileURL(pages, arguments[index], true)
+		case "--issue", "-issue":
+			value, index = optional(index, "")
+			number := 0
+			if value != "" {
+				number, ErrBuilding = strconv.Atoi(strings.TrimPrefix(value, "#"))
+				if ErrBuilding != nil {
+					ErrBuilding = fmt.Errorf("%s is not an issue number", value)
+				}
+			}
+			url = pages.Issue(number)
+		case "--actions", "-actions":
+			url = pages.Actions()
+		default:
+			if strings.HasPrefix(arguments[index], "-") {
+				return fmt.Errorf("%s is not recognised by the open command", arguments[index])
+			}
+			url, ErrBuilding = fileURL(pages, arguments[index], false)
+		}
+	}
+
+	if ErrBuilding != nil {
+		return ErrBuilding
+	}
+
+	if url == "" {
+		url = pages.Base
+	}
+
+	return opener(arguments).Open(url)
+}
+
+// fileURL links to a file given as path, path:10 or path:10-20, at the current commit
+func fileURL(pages git.Pages, argument string, blame bool) (string, error) {
+	file, start, end, ErrParsing := parseFileLines(argument)
+	if ErrParsing != nil {
+		return "", ErrParsing
+	}
+
+	if _, ErrFindingFile := os.Stat(file); ErrFindingFile != nil {
+		return "", ErrFindingFile
+	}
+
+	repoPath, ErrFindingPath := git.RepoPath(file)
+	if ErrFindingPath != nil {
+		return "", ErrFindingPath
+	}
+
+	commit, ErrResolving := git.ResolveCommit("HEAD")
+	if ErrResolving != nil {
+		return "", ErrResolving
+	}
+
+	if blame {
+		return pages.Blame(commit, repoPath, start, end), nil
+	}
+	return pages.File(commit, repoPath, start, end), nil
+}
+
+// parseFileLines splits path:10-20 into the path and lines, which are 0 when they're left off
+func parseFileLines(argument string) (string, int, int, error) {
+	file, lines, found := strings.Cut(argument, ":")
+	if !found {
+		return argument, 0, 0, nil
+	}
+
+	first, last, isRange := strings.Cut(lines, "-")
+
+	start, ErrParsingStart := strconv.Atoi(first)
+	if ErrParsingStart != nil || start < 1 {
+		return "", 0, 0, fmt.Errorf("%s should be a file with a line, like main.go:10 or main.go:10-20", argument)
+	}
+
+	end := start
+	if isRange {
+		var ErrParsingEnd error
+		end, ErrParsingEnd = strconv.Atoi(last)
+		if ErrParsingEnd != nil || end < start {
+			return "", 0, 0, fmt.Errorf("%s should be a file with a line, like main.go:10 or main.go:10-20", argument)
+		}
+	}
+
+	return file, start, end, nil
+}
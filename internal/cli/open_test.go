@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jonathon-chew/go-repoflow/internal/browser"
+)
+
+// fakeOpener records the URLs instead of opening them
+type fakeOpener struct {
+	urls []string
+	err  error
+}
+
+func (f *fakeOpener) Open(url string) error {
+	f.urls = append(f.urls, url)
+	return f.err
+}
+
+func TestPrintWithoutBrowser(t *testing.T) {
+	t.Log("Testing printWithoutBrowser only hides a missing browser")
+
+	fake := &fakeOpener{err: browser.ErrNoBrowser}
+	if err := (printWithoutBrowser{fake}).Open("https://example.com"); err != nil {
+		t.Errorf("a missing browser should print the URL, got %v", err)
+	}
+	if len(fake.urls) != 1 {
+		t.Errorf("expected the opener to be tried once, got %v", fake.urls)
+	}
+
+	if _, ok := opener([]string{"--open", "--print"}).(browser.Printer); !ok {
+		t.Error("--print should give a Printer")
+	}
+}
+
+func TestParseFileLines(t *testing.T) {
+	t.Log("Testing parseFileLines")
+
+	cases := []struct {
+		argument   string
+		file       string
+		start, end int
+	}{
+		{"main.go", "main.go", 0, 0},
+		{"internal/cli/open.go:10", "internal/cli/open.go", 10, 10},
+		{"open.go:10-20", "open.go", 10, 20},
+	}
+
+	for _, c := range cases {
+		file, start, end, err := parseFileLines(c.argument)
+		if err != nil || file != c.file || start != c.start || end != c.end {
+			t.Errorf("parseFileLines(%q) = %q, %d, %d, %v", c.argument, file, start, end, err)
+		}
+	}
+
+	for _, argument := range []string{"main.go:", "main.go:x", "main.go:20-10", "main.go:0"} {
+		if _, _, _, err := parseFileLines(argument); err == nil {
+			t.Errorf("parseFileLines(%q) should have failed", argument)
+		}
+	}
+}
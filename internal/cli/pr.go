@@ -0,0 +1,541 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+	"github.com/jonathon-chew/go-repoflow/internal/changelog"
+	"github.com/jonathon-chew/go-repoflow/internal/config"
+	"github.com/jonathon-chew/go-repoflow/internal/git"
+)
+
+// pullTemplates are the places GitHub looks for a pull request template, from the top of the repository
+var pullTemplates = []string{
+	".github/pull_request_template.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	"pull_request_template.md",
+	"PULL_REQUEST_TEMPLATE.md",
+	"docs/pull_request_template.md",
+	"docs/PULL_REQUEST_TEMPLATE.md",
+}
+
+// closingRE finds the issues the body already closes, so they aren't added twice
+var closingRE = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?)\s+#(\d+)\b`)
+
+// prCommand handles the pr subcommands
+func prCommand(arguments []string) error {
+	if len(arguments) == 0 {
+		return errors.New("pr needs a subcommand: create, list, view, checkout or status")
+	}
+
+	switch arguments[0] {
+	case "create":
+		return prCreateCommand(arguments[1:])
+	case "list":
+		return prListCommand(arguments[1:])
+	case "view":
+		return prViewCommand(arguments[1:])
+	case "checkout":
+		return prCheckoutCommand(arguments[1:])
+	case "status":
+		return prStatusCommand(arguments[1:])
+	}
+
+	return fmt.Errorf("%s is not a pr subcommand, use create, list, view, checkout or status", arguments[0])
+}
+
+// prCreateCommand pushes the current branch if it needs to be and opens a pull request for it
+func prCreateCommand(arguments []string) error {
+	var title, body, base string
+	var reviewers, labels []string
+	var draft bool
+	remote := "origin"
+
+	for index := 0; index < len(arguments); index++ {
+		switch arguments[index] {
+		case "--title", "-title", "--body", "-body", "--base", "-base", "--reviewer", "-reviewer", "--label", "-label", "--remote", "-remote":
+			if index+1 >= len(arguments) {
+				return fmt.Errorf("%s needs a value after it", arguments[index])
+			}
+			flag, value := strings.TrimLeft(arguments[index], "-"), arguments[index+1]
+			index++
+
+			switch flag {
+			case "title":
+				title = value
+			case "body":
+				body = value
+			case "base":
+				base = value
+			case "reviewer":
+				reviewers = append(reviewers, splitList(value)...)
+			case "label":
+				labels = append(labels, splitList(value)...)
+			case "remote":
+				remote = value
+			}
+		case "--draft", "-draft":
+			draft = true
+		case "--print", "-print":
+			// Read by opener, when the compare page is opened instead
+		default:
+			return fmt.Errorf("%s is not recognised by pr create", arguments[index])
+		}
+	}
+
+	branch, ErrGettingBranch := git.CurrentBranch()
+	if ErrGettingBranch != nil {
+		return ErrGettingBranch
+	}
+
+	if base == "" {
+		base = git.DefaultBranch(remote)
+	}
+	if branch == base {
+		return fmt.Errorf("%s is the base branch, check out the branch to open a pull request from", branch)
+	}
+
+	// Compare against the remote's copy of the base when there is one, the local branch may be behind it
+	baseRef := remote + "/" + base
+	if _, ErrResolving := git.ResolveCommit(baseRef); ErrResolving != nil {
+		baseRef = base
+		if _, ErrResolving := git.ResolveCommit(baseRef); ErrResolving != nil {
+			return fmt.Errorf("there's no %s branch here or on %s to open the pull request into, pick one with --base", base, remote)
+		}
+	}
+
+	entries, ErrGettingCommits := git.CommitsBetween(baseRef, branch)
+	if ErrGettingCommits != nil {
+		return ErrGettingCommits
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("%s has no commits which aren't on %s", branch, base)
+	}
+
+	if title == "" {
+		title = pullTitle(branch, entries)
+	}
+	if body == "" {
+		var template string
+		if root, ErrFindingRoot := git.RepoRoot(); ErrFindingRoot == nil {
+			template = pullTemplate(root)
+		}
+		body = pullBody(template, entries)
+	}
+	body = addClosingLines(body, entries)
+
+	pushed, ErrPushing := git.PushBranch(remote, branch)
+	if ErrPushing != nil {
+		return ErrPushing
+	}
+	if pushed {
+		aphrodite.PrintInfo(fmt.Sprintf("Pushed %s to %s\n", branch, remote))
+	}
+
+	settings, ErrLoadingConfig := config.Load()
+	if ErrLoadingConfig != nil {
+		return ErrLoadingConfig
+	}
+
+	pages, ErrGettingPages := git.RemotePages(settings.Forge)
+	if ErrGettingPages != nil {
+		return ErrGettingPages
+	}
+
+	// Without a token, or on another forge, the pull request is finished on the compare page
+	if pages.Forge != git.ForgeGitHub {
+		aphrodite.PrintInfo(fmt.Sprintf("Pull requests can only be created through the API on GitHub, opening the %s compare page instead\n", pages.Forge))
+		return opener(arguments).Open(pages.Compare(base, branch))
+	}
+	if os.Getenv("GH_PERSONAL_TOKEN") == "" {
+		aphrodite.PrintInfo("There's no GH_PERSONAL_TOKEN to create the pull request with, opening the compare page instead\n")
+		return opener(arguments).Open(pages.Compare(base, branch))
+	}
+
+	pull, ErrCreating := git.CreatePullRequest(git.Github_Pull_Request{
+		Title: title,
+		Head:  branch,
+		Base:  base,
+		Body:  body,
+		Draft: draft,
+	}, reviewers, labels)
+	if ErrCreating != nil {
+		return ErrCreating
+	}
+
+	fmt.Println(pull.Html_url)
+	return nil
+}
+
+// splitList splits a comma separated flag value, so --reviewer a,b and --reviewer a --reviewer b both work
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// pullTitle is the subject of the only commit, or else the branch name written as a sentence
+func pullTitle(branch string, entries []git.LogEntry) string {
+	if len(entries) == 1 {
+		subject, _, _ := strings.Cut(entries[0].Message, "\n")
+		return subject
+	}
+
+	// feature/login-page becomes Login page
+	name := branch[strings.LastIndex(branch, "/")+1:]
+	name = strings.TrimSpace(strings.NewReplacer("-", " ", "_", " ").Replace(name))
+	if name == "" {
+		return branch
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// pullTemplate reads the repository's pull request template, empty when it doesn't have one
+func pullTemplate(root string) string {
+	for _, name := range pullTemplates {
+		contents, err := os.ReadFile(filepath.Join(root, name))
+		if err == nil {
+			return strings.TrimSpace(string(contents))
+		}
+	}
+	return ""
+}
+
+// pullBody is the template when there is one, otherwise the body of the only commit or the subjects of every commit, oldest first
+func pullBody(template string, entries []git.LogEntry) string {
+	if template != "" {
+		return template
+	}
+
+	if len(entries) == 1 {
+		_, commitBody, _ := strings.Cut(entries[0].Message, "\n")
+		return strings.TrimSpace(commitBody)
+	}
+
+	var lines []string
+	for _, entry := range slices.Backward(entries) {
+		subject, _, _ := strings.Cut(entry.Message, "\n")
+		lines = append(lines, "- "+subject)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// addClosingLines adds Closes #N for each issue the commits mention which the body doesn't already close.
+// Merge commits are left out, the numbers in them are the pull requests they merged
+func addClosingLines(body string, entries []git.LogEntry) string {
+	var closed []int
+	for _, match := range closingRE.FindAllStringSubmatch(body, -1) {
+		number, _ := strconv.Atoi(match[1])
+		closed = append(closed, number)
+	}
+
+	var messages []string
+	for _, entry := range slices.Backward(entries) {
+		if !strings.HasPrefix(entry.Message, "Merge ") {
+			messages = append(messages, entry.Message)
+		}
+	}
+
+	var lines []string
+	for _, number := range changelog.IssueRefs(strings.Join(messages, "\n")) {
+		if !slices.Contains(closed, number) {
+			lines = append(lines, fmt.Sprintf("Closes #%d", number))
+		}
+	}
+
+	if len(lines) == 0 {
+		return body
+	}
+	if body == "" {
+		return strings.Join(lines, "\n")
+	}
+	return body + "\n\n" + strings.Join(lines, "\n")
+}
+
+// prListCommand prints the open pull requests with their review state and checks
+func prListCommand(arguments []string) error {
+	var asJSON bool
+	for _, argument := range arguments {
+		switch argument {
+		case "--json", "-json":
+			asJSON = true
+		default:
+			return fmt.Errorf("%s is not recognised by pr list", argument)
+		}
+	}
+
+	pulls, ErrListing := git.ListPullRequests(nil)
+	if ErrListing != nil {
+		return ErrListing
+	}
+
+	if asJSON {
+		return printJSON(pulls)
+	}
+
+	if len(pulls) == 0 {
+		fmt.Println("No open pull requests")
+		return nil
+	}
+
+	printPullTable(pulls)
+	return nil
+}
+
+// prViewCommand prints a pull request, the one for the current branch unless a number is given
+func prViewCommand(arguments []string) error {
+	var number int
+	var asJSON bool
+
+	for _, argument := range arguments {
+		switch argument {
+		case "--json", "-json":
+			asJSON = true
+		default:
+			parsed, ErrParsing := pullNumber(argument)
+			if ErrParsing != nil {
+				return ErrParsing
+			}
+			number = parsed
+		}
+	}
+
+	if number == 0 {
+		branchNumber, ErrFinding := currentBranchPull()
+		if ErrFinding != nil {
+			return ErrFinding
+		}
+		number = branchNumber
+	}
+
+	pull, ErrGetting := git.GetPullRequest(number)
+	if ErrGetting != nil {
+		return ErrGetting
+	}
+
+	if asJSON {
+		return printJSON(pull)
+	}
+
+	state := pull.State
+	switch {
+	case pull.Merged:
+		state = "merged"
+	case pull.Draft:
+		state = "draft"
+	}
+
+	aphrodite.PrintBold("Cyan", fmt.Sprintf("#%d %s\n", pull.Number, pull.Title))
+	fmt.Printf("%s, %s wants to merge %d commits from %s into %s\n", state, pull.User.Login, pull.Commits, pull.Head.Label, pull.Base.Ref)
+	fmt.Printf("%d files changed, +%d -%d\n", pull.Changed_files, pull.Additions, pull.Deletions)
+
+	if pull.Review != "" {
+		fmt.Printf("Review: %s\n", pull.Review)
+	}
+
+	if len(pull.Checks.Checks) > 0 {
+		fmt.Printf("Checks: %s\n", checksSummary(pull.Checks))
+		for _, check := range pull.Checks.Checks {
+			fmt.Printf("  %-8s  %s\n", check.State, check.Name)
+		}
+	}
+
+	if body := strings.TrimSpace(pull.Body); body != "" {
+		fmt.Printf("\n%s\n", body)
+	}
+
+	fmt.Printf("\n%s\n", pull.Html_url)
+	return nil
+}
+
+// prCheckoutCommand checks out the head of a pull request into a local branch
+func prCheckoutCommand(arguments []string) error {
+	var number int
+	remote := "origin"
+
+	for index := 0; index < len(arguments); index++ {
+		switch arguments[index] {
+		case "--remote", "-remote":
+			if index+1 >= len(arguments) {
+				return fmt.Errorf("%s needs the name of a remote after it", arguments[index])
+			}
+			index++
+			remote = arguments[index]
+		default:
+			parsed, ErrParsing := pullNumber(arguments[index])
+			if ErrParsing != nil {
+				return ErrParsing
+			}
+			number = parsed
+		}
+	}
+
+	if number == 0 {
+		return errors.New("pr checkout needs the number of the pull request")
+	}
+
+	branch, ErrCheckingOut := git.CheckoutPullRequest(remote, number)
+	if ErrCheckingOut != nil {
+		return ErrCheckingOut
+	}
+
+	aphrodite.PrintInfo(fmt.Sprintf("Checked out #%d as %s\n", number, branch))
+	return nil
+}
+
+// prStatusCommand summarises the pull request for the current branch, the ones you opened and the ones waiting for your review
+func prStatusCommand(arguments []string) error {
+	var asJSON bool
+	for _, argument := range arguments {
+		switch argument {
+		case "--json", "-json":
+			asJSON = true
+		default:
+			return fmt.Errorf("%s is not recognised by pr status", argument)
+		}
+	}
+
+	login, ErrGettingLogin := git.GithubLogin()
+	if ErrGettingLogin != nil {
+		return ErrGettingLogin
+	}
+
+	// Detached HEAD has no pull request, which isn't worth failing over
+	branch, _ := git.CurrentBranch()
+
+	isCurrent := func(pull git.Github_Pull) bool { return branch != "" && pull.Head.Ref == branch }
+	isAuthored := func(pull git.Github_Pull) bool { return pull.User.Login == login }
+	isReviewing := func(pull git.Github_Pull) bool {
+		return slices.ContainsFunc(pull.Requested_reviewers, func(reviewer git.Github_Assignee) bool { return reviewer.Login == login })
+	}
+
+	// Only the pull requests shown need their reviews and checks fetching
+	pulls, ErrListing := git.ListPullRequests(func(pull git.Github_Pull) bool {
+		return isCurrent(pull) || isAuthored(pull) || isReviewing(pull)
+	})
+	if ErrListing != nil {
+		return ErrListing
+	}
+
+	var current, authored, reviewing []git.PullStatus
+	for _, pull := range pulls {
+		if isCurrent(pull.Github_Pull) {
+			current = append(current, pull)
+		}
+		if isAuthored(pull.Github_Pull) {
+			authored = append(authored, pull)
+		}
+		if isReviewing(pull.Github_Pull) {
+			reviewing = append(reviewing, pull)
+		}
+	}
+
+	if asJSON {
+		return printJSON(struct {
+			CurrentBranch []git.PullStatus `json:"current_branch"`
+			CreatedByYou  []git.PullStatus `json:"created_by_you"`
+			ReviewNeeded  []git.PullStatus `json:"requesting_your_review"`
+		}{nonNil(current), nonNil(authored), nonNil(reviewing)})
+	}
+
+	for _, section := range []struct {
+		heading string
+		pulls   []git.PullStatus
+	}{
+		{"Current branch", current},
+		{"Created by you", authored},
+		{"Requesting a review from you", reviewing},
+	} {
+		aphrodite.PrintBold("Cyan", section.heading+"\n")
+		if len(section.pulls) == 0 {
+			fmt.Print("  None\n\n")
+			continue
+		}
+		printPullTable(section.pulls)
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// printPullTable prints one line for each pull request
+func printPullTable(pulls []git.PullStatus) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "#\tTITLE\tBRANCH\tAUTHOR\tREVIEW\tCHECKS")
+
+	for _, pull := range pulls {
+		title := pull.Title
+		if pull.Draft {
+			title = "[draft] " + title
+		}
+
+		review := pull.Review
+		if review == "" {
+			review = "-"
+		}
+
+		fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\t%s\n", pull.Number, title, pull.Head.Ref, pull.User.Login, review, checksSummary(pull.Checks))
+	}
+	writer.Flush()
+}
+
+// checksSummary is the state of the checks with how many passed, such as failing (3/4)
+func checksSummary(checks git.Checks) string {
+	if checks.State == "" {
+		return "-"
+	}
+	return fmt.Sprintf("%s (%d/%d)", checks.State, checks.Passing, checks.Passing+checks.Failing+checks.Pending)
+}
+
+// pullNumber reads a pull request number written as 12 or #12
+func pullNumber(argument string) (int, error) {
+	number, err := strconv.Atoi(strings.TrimPrefix(argument, "#"))
+	if err != nil || number < 1 {
+		return 0, fmt.Errorf("%s is not the number of a pull request", argument)
+	}
+	return number, nil
+}
+
+// currentBranchPull is the number of the open pull request from the checked out branch
+func currentBranchPull() (int, error) {
+	branch, ErrGettingBranch := git.CurrentBranch()
+	if ErrGettingBranch != nil {
+		return 0, ErrGettingBranch
+	}
+
+	number, ErrFinding := git.BranchPullRequest(branch)
+	if ErrFinding != nil {
+		return 0, ErrFinding
+	}
+	if number == 0 {
+		return 0, fmt.Errorf("there's no open pull request for %s, give the number of one", branch)
+	}
+	return number, nil
+}
+
+// nonNil keeps empty lists as [] rather than null in the JSON
+func nonNil(pulls []git.PullStatus) []git.PullStatus {
+	if pulls == nil {
+		return []git.PullStatus{}
+	}
+	return pulls
+}
+
+func printJSON(value any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
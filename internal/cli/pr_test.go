@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jonathon-chew/go-repoflow/internal/git"
+)
+
+// Newest first, as git log gives them
+var pullEntries = []git.LogEntry{
+	{Message: "fix: handle the empty list\n\nFixes #7"},
+	{Message: "feat: add the login page (#12)"},
+}
+
+func TestPullTitle(t *testing.T) {
+	t.Log("Testing pullTitle")
+
+	cases := []struct {
+		branch  string
+		entries []git.LogEntry
+		want    string
+	}{
+		{"feature/login-page", pullEntries, "Login page"},
+		{"jc/fix_the_crash", pullEntries, "Fix the crash"},
+		{"feature/login-page", pullEntries[1:], "feat: add the login page (#12)"},
+	}
+
+	for _, c := range cases {
+		if got := pullTitle(c.branch, c.entries); got != c.want {
+			t.Errorf("pullTitle(%q) = %q, want %q", c.branch, got, c.want)
+		}
+	}
+}
+
+func TestPullBody(t *testing.T) {
+	t.Log("Testing pullBody and addClosingLines")
+
+	if got, want := pullBody("", pullEntries), "- feat: add the login page (#12)\n- fix: handle the empty list"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := pullBody("", pullEntries[:1]), "Fixes #7"; got != want {
+		t.Errorf("a single commit should use its body, got %q", got)
+	}
+	if got := pullBody("## Summary", pullEntries); got != "## Summary" {
+		t.Errorf("the template should be used when there is one, got %q", got)
+	}
+
+	if got, want := addClosingLines("## Summary", pullEntries), "## Summary\n\nCloses #12\nCloses #7"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := addClosingLines("Resolves #7", pullEntries), "Resolves #7\n\nCloses #12"; got != want {
+		t.Errorf("issues the body already closes shouldn't be added again, got %q", got)
+	}
+
+	entries := []git.LogEntry{
+		{Message: "Merge pull request #40 from jc/login\n\nFixes #41"},
+		{Message: "fix: retry the upload (#9)\n\nSee #10"},
+	}
+	if got, want := addClosingLines("", entries), "Closes #9\nCloses #10"; got != want {
+		t.Errorf("merge commits shouldn't be closed, got %q, want %q", got, want)
+	}
+}
+
+func TestPullTemplate(t *testing.T) {
+	t.Log("Testing pullTemplate finds the template GitHub would use")
+
+	root := t.TempDir()
+	if got := pullTemplate(root); got != "" {
+		t.Errorf("expected no template, got %q", got)
+	}
+
+	os.MkdirAll(filepath.Join(root, "docs"), 0o755)
+	os.WriteFile(filepath.Join(root, "docs", "pull_request_template.md"), []byte("## Changes\n"), 0o644)
+
+	if got := pullTemplate(root); got != "## Changes" {
+		t.Errorf("got %q, want the docs template", got)
+	}
+}
+
+func TestPullNumber(t *testing.T) {
+	t.Log("Testing pullNumber")
+
+	for _, argument := range []string{"12", "#12"} {
+		if number, err := pullNumber(argument); err != nil || number != 12 {
+			t.Errorf("pullNumber(%q) = %d, %v", argument, number, err)
+		}
+	}
+
+	for _, argument := range []string{"", "#", "0", "twelve", "--json"} {
+		if _, err := pullNumber(argument); err == nil {
+			t.Errorf("pullNumber(%q) should have failed", argument)
+		}
+	}
+}
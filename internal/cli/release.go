@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+	"github.com/jonathon-chew/go-repoflow/internal/git"
+)
+
+// releaseCommand creates or updates the GitHub Release for a tag, the latest one unless --tag is given
+func releaseCommand(arguments []string) error {
+	var tag, title, notesFile string
+	remote := "origin"
+	var assets []string
+
+	for index := 0; index < len(arguments); index++ {
+		switch arguments[index] {
+		case "--tag", "-tag":
+			if index+1 >= len(arguments) {
+				return fmt.Errorf("%s needs a tag after it", arguments[index])
+			}
+			index++
+			tag = arguments[index]
+		case "--title", "-title":
+			if index+1 >= len(arguments) {
+				return fmt.Errorf("%s needs a title after it", arguments[index])
+			}
+			index++
+			title = arguments[index]
+		case "--notes-file", "-notes-file":
+			if index+1 >= len(arguments) {
+				return fmt.Errorf("%s needs a file after it", arguments[index])
+			}
+			index++
+			notesFile = arguments[index]
+		case "--remote", "-remote":
+			if index+1 >= len(arguments) {
+				return fmt.Errorf("%s needs a remote after it", arguments[index])
+			}
+			index++
+			remote = arguments[index]
+		default:
+			if strings.HasPrefix(arguments[index], "-") {
+				return fmt.Errorf("%s is not recognised by the release command", arguments[index])
+			}
+			assets = append(assets, arguments[index])
+		}
+	}
+
+	// Check the assets before anything is sent to GitHub
+	for _, asset := range assets {
+		info, ErrFindingAsset := os.Stat(asset)
+		if ErrFindingAsset != nil {
+			return ErrFindingAsset
+		}
+		if info.IsDir() {
+			return fmt.Errorf("%s is a directory, assets need to be files", asset)
+		}
+	}
+
+	if tag == "" {
+		latestTag, ErrGetLatestTag := git.GetLatestTag()
+		if ErrGetLatestTag != nil {
+			return ErrGetLatestTag
+		}
+		tag = latestTag
+	}
+
+	var notes string
+	if notesFile != "" {
+		contents, ErrReadingNotes := os.ReadFile(notesFile)
+		if ErrReadingNotes != nil {
+			return ErrReadingNotes
+		}
+		notes = string(contents)
+	} else {
+		// Generate the notes from the tag before this one, or the whole history for the first release
+		previousTag, ErrFindingPrevious := git.PreviousTag(tag)
+		if ErrFindingPrevious != nil {
+			return ErrFindingPrevious
+		}
+
+		generated, ErrGenerating := releaseNotes(previousTag, tag, tag)
+		if ErrGenerating != nil {
+			return ErrGenerating
+		}
+		notes = generated
+	}
+
+	release, ErrPublishing := git.PublishRelease(tag, title, notes, remote, assets)
+	if ErrPublishing != nil {
+		return ErrPublishing
+	}
+
+	if release.Prerelease {
+		aphrodite.PrintInfo(fmt.Sprintf("%s is marked as a pre-release\n", tag))
+	}
+	fmt.Println(release.Html_url)
+
+	return nil
+}
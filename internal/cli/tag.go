@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+	"github.com/jonathon-chew/go-repoflow/internal/commits"
+	"github.com/jonathon-chew/go-repoflow/internal/config"
+	"github.com/jonathon-chew/go-repoflow/internal/git"
+)
+
+// tagCommand handles the tag subcommands
+func tagCommand(arguments []string) error {
+	var auto, bumpFiles bool
+	var options git.TagOptions
+
+	if len(arguments) > 0 && arguments[0] == "verify" {
+		return tagVerifyCommand(arguments[1:])
+	}
+
+	for index := 0; index < len(arguments); index++ {
+		switch arguments[index] {
+		case "--auto", "-auto", "-a":
+			auto = true
+		default:
+			next, ok, ErrParsingFlag := tagFlag(arguments, index, &options, &bumpFiles)
+			if ErrParsingFlag != nil {
+				return ErrParsingFlag
+			}
+			if !ok {
+				return fmt.Errorf("%s is not recognised by the tag command", arguments[index])
+			}
+			index = next
+		}
+	}
+
+	if !auto {
+		return errors.New("tag needs --auto, or use --increment-tag to choose the bump yourself")
+	}
+
+	if bumpFiles {
+		if ErrFindingFiles := addVersionFiles(&options); ErrFindingFiles != nil {
+			return ErrFindingFiles
+		}
+	}
+
+	return autoTag(options)
+}
+
+// latestTags prints the latest tag, or the latest tag of each module in a monorepo
+func latestTags() error {
+	settings, ErrLoadingConfig := config.Load()
+	if ErrLoadingConfig != nil {
+		return ErrLoadingConfig
+	}
+
+	modules, ErrFindingModules := git.FindModules(settings)
+	if ErrFindingModules != nil {
+		return ErrFindingModules
+	}
+
+	// Without any modules keep printing just the tag, scripts read it
+	if len(modules) == 1 {
+		version, ErrGetLatestTag := git.GetLatestTag()
+		if ErrGetLatestTag != nil {
+			return ErrGetLatestTag
+		}
+		fmt.Println(version)
+		return nil
+	}
+
+	width := 0
+	for _, module := range modules {
+		width = max(width, len(module.Name()))
+	}
+
+	for _, module := range modules {
+		version, ErrGetLatestTag := git.LatestModuleTag(module.TagPrefix)
+		if ErrGetLatestTag != nil {
+			return ErrGetLatestTag
+		}
+		if version == "" {
+			version = "(no tags)"
+		}
+		fmt.Printf("%-*s  %s\n", width, module.Name(), version)
+	}
+
+	return nil
+}
+
+// tagFlag reads the flag at index if it's one shared by --increment-tag and tag, returning the index of the last argument it used.
+// --bump-files only sets bumpFiles, call addVersionFiles after every flag has been read
+func tagFlag(arguments []string, index int, options *git.TagOptions, bumpFiles *bool) (int, bool, error) {
+	switch arguments[index] {
+	case "--preid", "-preid":
+		if index+1 >= len(arguments) {
+			return index, true, errors.New("--preid needs a pre-release name after it, such as rc")
+		}
+		index++
+		options.Preid = arguments[index]
+	case "--changelog", "-changelog":
+		// Read the module when the tag is made, --module may come after this
+		options.Message = func(previousTag, newTag string) (string, error) {
+			return changelogMessage(options.Module)(previousTag, newTag)
+		}
+	case "--module", "-module":
+		if index+1 >= len(arguments) {
+			return index, true, errors.New("--module needs the path of a module after it, such as services/api")
+		}
+		index++
+
+		settings, ErrLoadingConfig := config.Load()
+		if ErrLoadingConfig != nil {
+			return index, true, ErrLoadingConfig
+		}
+
+		module, ErrFindingModule := git.FindModule(settings, arguments[index])
+		if ErrFindingModule != nil {
+			return index, true, ErrFindingModule
+		}
+		options.Module = module
+	case "--if-changed", "-if-changed":
+		options.OnlyIfChanged = true
+	case "--yes", "-yes", "-y":
+		options.Yes = true
+	case "--push", "-push":
+		options.Push = git.PushAlways
+	case "--no-push", "-no-push":
+		options.Push = git.PushNever
+	case "--remote", "-remote":
+		if index+1 >= len(arguments) {
+			return index, true, errors.New("--remote needs the name of a remote after it, such as origin")
+		}
+		index++
+		options.Remote = arguments[index]
+	case "--dry-run", "-dry-run", "-n":
+		options.DryRun = true
+	case "--sign", "-sign", "-s":
+		options.Sign = true
+	case "--bump-files", "-bump-files":
+		// The files depend on the module, which may come after this
+		*bumpFiles = true
+	default:
+		return index, false, nil
+	}
+
+	return index, true, nil
+}
+
+// addVersionFiles sets the files for --bump-files to write the new version into, once the module is known
+func addVersionFiles(options *git.TagOptions) error {
+	settings, ErrLoadingConfig := config.Load()
+	if ErrLoadingConfig != nil {
+		return ErrLoadingConfig
+	}
+
+	versionFiles, ErrFindingFiles := git.FindVersionFiles(settings, options.Module)
+	if ErrFindingFiles != nil {
+		return ErrFindingFiles
+	}
+
+	if len(versionFiles) == 0 {
+		return fmt.Errorf("--bump-files found no package.json, Cargo.toml or VERSION file and there are no version_files in %s", config.FileName)
+	}
+
+	options.VersionFiles = versionFiles
+	return nil
+}
+
+// tagVerifyCommand reports the signature of the latest tag, the tags given or every release tag with --all, failing if any aren't signed and valid
+func tagVerifyCommand(arguments []string) error {
+	var tags []string
+	var all bool
+
+	for _, argument := range arguments {
+		switch argument {
+		case "--all", "-all", "-a":
+			all = true
+		default:
+			if strings.HasPrefix(argument, "-") {
+				return fmt.Errorf("%s is not recognised by tag verify", argument)
+			}
+			tags = append(tags, argument)
+		}
+	}
+
+	switch {
+	case all:
+		releaseTags, ErrGettingTags := git.ReleaseTags()
+		if ErrGettingTags != nil {
+			return ErrGettingTags
+		}
+		tags = append(tags, releaseTags...)
+	case len(tags) == 0:
+		latestTag, ErrGetLatestTag := git.GetLatestTag()
+		if ErrGetLatestTag != nil {
+			return ErrGetLatestTag
+		}
+		if latestTag == "" {
+			return errors.New("there are no release tags to verify")
+		}
+		tags = append(tags, latestTag)
+	}
+
+	var failed int
+	for _, tag := range tags {
+		signature, ErrVerifying := git.VerifyTag(tag)
+		if ErrVerifying != nil {
+			return ErrVerifying
+		}
+
+		line := fmt.Sprintf("%s: %s", tag, signature.Status)
+		if signature.Format != "" {
+			line += " (" + signature.Format + ")"
+		}
+		if signature.Detail != "" {
+			line += " " + signature.Detail
+		}
+
+		if signature.Status == git.SignatureGood {
+			aphrodite.PrintColour("Green", line+"\n")
+		} else {
+			aphrodite.PrintColour("Red", line+"\n")
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d tags are not signed with a valid signature", failed, len(tags))
+	}
+
+	return nil
+}
+
+// autoTag reads the Conventional Commits since the latest tag, explains which bump they need and makes the tag
+func autoTag(options git.TagOptions) error {
+	settings, ErrLoadingConfig := config.Load()
+	if ErrLoadingConfig != nil {
+		return ErrLoadingConfig
+	}
+
+	latestTag, ErrGetLatestTag := git.LatestModuleTag(options.Module.TagPrefix)
+	if ErrGetLatestTag != nil {
+		return ErrGetLatestTag
+	}
+
+	// The very first tag doesn't need a reason
+	if latestTag == "" {
+		return git.NewGitTag("", options)
+	}
+
+	_, latestVersion, ErrParsing := git.ParseModuleTag(latestTag)
+	if ErrParsing != nil {
+		return ErrParsing
+	}
+
+	// Only the commits to the module count towards its version
+	var paths []string
+	if options.Module.Path != "" {
+		paths = append(paths, options.Module.Path)
+	}
+
+	entries, ErrGettingCommits := git.CommitsBetween(latestTag, "", paths...)
+	if ErrGettingCommits != nil {
+		return ErrGettingCommits
+	}
+
+	if len(entries) == 0 && options.OnlyIfChanged {
+		aphrodite.PrintInfo(fmt.Sprintf("Nothing in %s has changed since %s, so it hasn't been tagged\n", options.Module.Name(), latestTag))
+		return nil
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("there are no commits since %s to release", latestTag)
+	}
+
+	var parsed []commits.Commit
+	for _, entry := range entries {
+		commit := commits.Parse(entry.Message)
+		commit.Hash, commit.Author, commit.Email = entry.Hash, entry.Author, entry.Email
+		parsed = append(parsed, commit)
+	}
+
+	preOne := latestVersion.Major == 0
+	bump, reasons := commits.NextBump(parsed, preOne, settings.PreOneBreakingBump)
+
+	if bump == commits.BumpNone {
+		return fmt.Errorf("none of the %d commits since %s are a feat, fix or breaking change, so there is nothing to release", len(parsed), latestTag)
+	}
+
+	// Show the reasoning before anything is tagged
+	aphrodite.PrintBold("Cyan", fmt.Sprintf("%d commits since %s, a %s bump is needed because of:\n", len(parsed), latestTag, bump))
+	for _, commit := range reasons {
+		header := commit.Type
+		if commit.Scope != "" {
+			header += "(" + commit.Scope + ")"
+		}
+		if commit.Breaking {
+			header += "!"
+		}
+		fmt.Printf("  %s %s: %s\n", shortHash(commit.Hash), header, commit.Description)
+	}
+
+	if preOne && bump == commits.BumpMinor && hasBreaking(reasons) && settings.PreOneBreakingBump != commits.BumpMajor {
+		aphrodite.PrintInfo("Breaking changes bump the minor version before 1.0.0, set pre_1_0_breaking_bump to major in .repoflow.json to change this\n")
+	}
+
+	return git.NewGitTag(bump, options)
+}
+
+func hasBreaking(reasons []commits.Commit) bool {
+	for _, commit := range reasons {
+		if commit.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jonathon-chew/go-repoflow/internal/git"
+)
+
+func TestTagFlag(t *testing.T) {
+	t.Log("Testing tagFlag reads the push, remote and dry run flags")
+
+	cases := []struct {
+		arguments []string
+		want      git.TagOptions
+	}{
+		{[]string{"--yes"}, git.TagOptions{Yes: true}},
+		{[]string{"-y"}, git.TagOptions{Yes: true}},
+		{[]string{"--push"}, git.TagOptions{Push: git.PushAlways}},
+		{[]string{"--no-push"}, git.TagOptions{Push: git.PushNever}},
+		{[]string{"--yes", "--no-push"}, git.TagOptions{Yes: true, Push: git.PushNever}},
+		{[]string{"--remote", "upstream"}, git.TagOptions{Remote: "upstream"}},
+		{[]string{"--dry-run", "--push", "-remote", "fork"}, git.TagOptions{DryRun: true, Push: git.PushAlways, Remote: "fork"}},
+		{[]string{"-n", "--preid", "beta"}, git.TagOptions{DryRun: true, Preid: "beta"}},
+	}
+
+	for _, c := range cases {
+		var options git.TagOptions
+		var bumpFiles bool
+
+		for index := 0; index < len(c.arguments); index++ {
+			next, ok, err := tagFlag(c.arguments, index, &options, &bumpFiles)
+			if err != nil || !ok {
+				t.Fatalf("tagFlag(%v) at %d = %v, %v", c.arguments, index, ok, err)
+			}
+			index = next
+		}
+
+		if options.Yes != c.want.Yes || options.Push != c.want.Push || options.Remote != c.want.Remote || options.DryRun != c.want.DryRun || options.Preid != c.want.Preid {
+			t.Errorf("tagFlag(%v) gave %+v, want %+v", c.arguments, options, c.want)
+		}
+	}
+
+	// Flags needing a value fail without one, and other arguments are left for the caller
+	var options git.TagOptions
+	var bumpFiles bool
+	for _, flag := range []string{"--remote", "--preid"} {
+		if _, ok, err := tagFlag([]string{flag}, 0, &options, &bumpFiles); !ok || err == nil {
+			t.Errorf("%s without a value should have failed", flag)
+		}
+	}
+	if _, ok, err := tagFlag([]string{"patch"}, 0, &options, &bumpFiles); ok || err != nil {
+		t.Errorf("patch should be left for the caller, got %v, %v", ok, err)
+	}
+}
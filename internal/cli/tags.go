@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+	"github.com/jonathon-chew/go-repoflow/internal/config"
+	"github.com/jonathon-chew/go-repoflow/internal/git"
+	"github.com/jonathon-chew/go-repoflow/internal/semver"
+)
+
+// tagsCommand handles the tags subcommands
+func tagsCommand(arguments []string) error {
+	if len(arguments) == 0 || arguments[0] != "list" {
+		return errors.New("tags needs a subcommand: list")
+	}
+
+	return tagsListCommand(arguments[1:])
+}
+
+// tagsListCommand prints every version tag, newest first, with its history
+func tagsListCommand(arguments []string) error {
+	var since, modulePath, remote string
+	var limit int
+	var onlyPrerelease, noPrerelease, asJSON, byModule bool
+
+	for index := 0; index < len(arguments); index++ {
+		switch arguments[index] {
+		case "--since", "-since":
+			if index+1 >= len(arguments) {
+				return fmt.Errorf("%s needs a date (YYYY-MM-DD) or a tag after it", arguments[index])
+			}
+			index++
+			since = arguments[index]
+		case "--limit", "-limit":
+			if index+1 >= len(arguments) {
+				return fmt.Errorf("%s needs a number after it", arguments[index])
+			}
+			index++
+			number, ErrParsing := strconv.Atoi(arguments[index])
+			if ErrParsing != nil || number < 1 {
+				return fmt.Errorf("%s is not a number of tags to show", arguments[index])
+			}
+			limit = number
+		case "--module", "-module":
+			if index+1 >= len(arguments) {
+				return fmt.Errorf("%s needs the path of a module after it", arguments[index])
+			}
+			index++
+			modulePath, byModule = arguments[index], true
+		case "--remote", "-remote":
+			if index+1 >= len(arguments) {
+				return fmt.Errorf("%s needs the name of a remote after it", arguments[index])
+			}
+			index++
+			remote = arguments[index]
+		case "--prerelease", "-prerelease":
+			onlyPrerelease = true
+		case "--no-prerelease", "-no-prerelease":
+			noPrerelease = true
+		case "--json", "-json":
+			asJSON = true
+		default:
+			return fmt.Errorf("%s is not recognised by tags list", arguments[index])
+		}
+	}
+
+	tags, others, ErrListingTags := git.ListTags(remote)
+	if ErrListingTags != nil {
+		return ErrListingTags
+	}
+
+	if byModule {
+		settings, ErrLoadingConfig := config.Load()
+		if ErrLoadingConfig != nil {
+			return ErrLoadingConfig
+		}
+
+		module, ErrFindingModule := git.FindModule(settings, modulePath)
+		if ErrFindingModule != nil {
+			return ErrFindingModule
+		}
+
+		tags = filterTags(tags, func(tag git.TagInfo) bool { return tag.Module == module.TagPrefix })
+	}
+
+	switch {
+	case onlyPrerelease:
+		tags = filterTags(tags, func(tag git.TagInfo) bool { return tag.Prerelease })
+	case noPrerelease:
+		tags = filterTags(tags, func(tag git.TagInfo) bool { return !tag.Prerelease })
+	}
+
+	if since != "" {
+		keep, ErrParsingSince := sinceFilter(since)
+		if ErrParsingSince != nil {
+			return ErrParsingSince
+		}
+		tags = filterTags(tags, keep)
+	}
+
+	if limit > 0 && len(tags) > limit {
+		tags = tags[:limit]
+	}
+
+	if asJSON {
+		if tags == nil {
+			tags = []git.TagInfo{}
+		}
+		if others == nil {
+			others = []string{}
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			Tags      []git.TagInfo `json:"tags"`
+			OtherTags []string      `json:"other_tags"`
+		}{tags, others})
+	}
+
+	if len(tags) == 0 {
+		fmt.Println("No version tags found")
+	} else {
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "TAG\tDATE\tTAGGER\tCOMMIT\tCOMMITS\tPUSHED")
+
+		for _, tag := range tags {
+			tagger := tag.Tagger
+			if tagger == "" {
+				tagger = "-"
+			}
+
+			pushed := "?"
+			if tag.Pushed != nil {
+				pushed = map[bool]string{true: "yes", false: "no"}[*tag.Pushed]
+			}
+
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%d\t%s\n", tag.Name, tag.Date.Format(time.DateOnly), tagger, shortHash(tag.Commit), tag.Commits, pushed)
+		}
+		writer.Flush()
+
+		if tags[0].Pushed == nil {
+			aphrodite.PrintInfo("The remote couldn't be reached, so whether the tags are pushed isn't known\n")
+		}
+	}
+
+	// Tags that aren't versions are kept out of the way rather than warned about one by one
+	if len(others) > 0 {
+		fmt.Printf("\nNot semantic versions: %s\n", strings.Join(others, ", "))
+	}
+
+	return nil
+}
+
+func filterTags(tags []git.TagInfo, keep func(git.TagInfo) bool) []git.TagInfo {
+	var kept []git.TagInfo
+	for _, tag := range tags {
+		if keep(tag) {
+			kept = append(kept, tag)
+		}
+	}
+	return kept
+}
+
+// sinceFilter keeps the tags made on or after a date, or the later versions of the same module for a tag
+func sinceFilter(since string) (func(git.TagInfo) bool, error) {
+	if day, ErrParsingDate := time.ParseInLocation(time.DateOnly, since, time.Local); ErrParsingDate == nil {
+		return func(tag git.TagInfo) bool { return !tag.Date.Before(day) }, nil
+	}
+
+	prefix, version, ErrParsingTag := git.ParseModuleTag(since)
+	if ErrParsingTag != nil {
+		return nil, fmt.Errorf("--since needs a date (YYYY-MM-DD) or a version tag, %s is neither", since)
+	}
+
+	return func(tag git.TagInfo) bool {
+		return tag.Module == prefix && semver.Compare(tag.Version, version) > 0
+	}, nil
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"time"
+
+	"github.com/jonathon-chew/go-repoflow/internal/git"
+	"github.com/jonathon-chew/go-repoflow/internal/todo"
+)
+
+// ErrCheckFailed is returned once a check has reported its findings, so only the exit code needs to show it failed
+var ErrCheckFailed = errors.New("the check failed")
+
+// todosCommand lists every TODO in the tree without talking to the remote, so it works without a token or an origin
+func todosCommand(arguments []string) error {
+	if len(arguments) > 0 && arguments[0] == "check" {
+		return todosCheckCommand(arguments[1:])
+	}
+
+	var format, groupBy string = "text", "directory"
+
+	for index := 0; index < len(arguments); index++ {
+		switch arguments[index] {
+		case "--format", "-format", "-f":
+			if index+1 >= len(arguments) {
+				return fmt.Errorf("%s needs one of markdown, json or csv after it", arguments[index])
+			}
+			index++
+			format = arguments[index]
+		case "--group-by", "-group-by", "--group", "-group", "-g":
+			if index+1 >= len(arguments) {
+				return fmt.Errorf("%s needs directory or author after it", arguments[index])
+			}
+			index++
+			groupBy = arguments[index]
+		default:
+			return fmt.Errorf("%s is not recognised by the todos command", arguments[index])
+		}
+	}
+
+	files, ErrFindingFiles := todo.FindFiles(".")
+	if ErrFindingFiles != nil {
+		return ErrFindingFiles
+	}
+
+	// Stop scanning on Ctrl-C rather than waiting for a large tree to finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	results, ErrScanning := todo.ScanFiles(ctx, files, 0)
+	if ErrScanning != nil {
+		return ErrScanning
+	}
+
+	var entries []todo.ReportEntry
+	now := time.Now()
+
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Fprintf(os.Stderr, "[WARNING]: Skipping %s: %s\n", result.Path, result.Err)
+			continue
+		}
+
+		if len(result.Todos) == 0 {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// Untracked files, or a folder that isn't a repo, just don't have an author
+		blames, _ := git.BlameFile(result.Path)
+
+		for _, found := range result.Todos {
+			entry := todo.ReportEntry{
+				File:   found.File,
+				Line:   found.Line,
+				Marker: found.Marker,
+				Issue:  found.Issue,
+				Title:  found.Title,
+			}
+
+			if found.Line <= len(blames) {
+				blame := blames[found.Line-1]
+				entry.Author = blame.Author
+				if !blame.Uncommitted() && !blame.AuthorTime.IsZero() {
+					entry.AgeDays = int(now.Sub(blame.AuthorTime).Hours() / 24)
+				}
+			}
+
+			entries = append(entries, entry)
+		}
+	}
+
+	groups, ErrGrouping := todo.GroupEntries(entries, groupBy)
+	if ErrGrouping != nil {
+		return ErrGrouping
+	}
+
+	return todo.WriteReport(os.Stdout, groups, format)
+}
+
+// todosCheckCommand fails when lines added since the base ref have a TODO without an issue reference, for CI and pre-commit
+func todosCheckCommand(arguments []string) error {
+	var format, base string = "text", "origin/main"
+	var staged bool
+
+	for index := 0; index < len(arguments); index++ {
+		switch arguments[index] {
+		case "--format", "-format", "-f":
+			if index+1 >= len(arguments) {
+				return fmt.Errorf("%s needs one of text, github or sarif after it", arguments[index])
+			}
+			index++
+			format = arguments[index]
+		case "--base", "-base", "-b":
+			if index+1 >= len(arguments) {
+				return fmt.Errorf("%s needs a git ref after it", arguments[index])
+			}
+			index++
+			base = arguments[index]
+		case "--staged", "-staged", "--cached":
+			staged = true
+		default:
+			return fmt.Errorf("%s is not recognised by the todos check command", arguments[index])
+		}
+	}
+
+	var addedLines []git.AddedLine
+	var ErrGettingDiff error
+
+	// The pre-commit hook checks what is about to be committed, not what is in the working tree
+	if staged {
+		addedLines, ErrGettingDiff = git.StagedAddedLines()
+	} else {
+		addedLines, ErrGettingDiff = git.AddedLinesSince(base)
+	}
+	if ErrGettingDiff != nil {
+		return ErrGettingDiff
+	}
+
+	// Leave out the same files as a scan, those skipped by name or .gitattributes and the binary and generated ones
+	var files []string
+	for _, added := range addedLines {
+		if !slices.Contains(files, added.File) {
+			files = append(files, added.File)
+		}
+	}
+
+	scannable := map[string]bool{}
+	for _, file := range todo.FilterScannable(files) {
+		contents, ErrReading := checkedContents(file, staged)
+		scannable[file] = ErrReading != nil || todo.Sniff(contents) == nil
+	}
+
+	var findings []todo.Todo
+	for _, added := range addedLines {
+		if !scannable[added.File] {
+			continue
+		}
+
+		item, ok := todo.Parse(added.Text)
+		if !ok || item.Issue != 0 {
+			continue
+		}
+
+		findings = append(findings, todo.Todo{Item: item, File: added.File, Line: added.Line})
+	}
+
+	if ErrWriting := todo.WriteFindings(os.Stdout, findings, format); ErrWriting != nil {
+		return ErrWriting
+	}
+
+	// The findings may be a SARIF file being redirected, so the summary goes to stderr
+	if len(findings) > 0 {
+		fmt.Fprintf(os.Stderr, "Found %d new TODOs without an issue reference\n", len(findings))
+		return ErrCheckFailed
+	}
+
+	return nil
+}
+
+// checkedContents is the version of the file todos check looks at, the staged one for the pre-commit hook
+func checkedContents(path string, staged bool) ([]byte, error) {
+	if staged {
+		return git.StagedFile(path)
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Version is the latest release, reported when neither -ldflags nor Go's build information give a version, such as with go run.
+// Tagging with --bump-files keeps it the same as the latest tag, see version_files in .repoflow.json
+var Version string = "v0.7.7"
+
+// These override what Go embeds in the binary when set at build time, scripts/CICD.sh sets them from git describe:
+//
+//	go build -ldflags "-X github.com/jonathon-chew/go-repoflow/internal/cli.buildVersion=v1.2.3" ./cmd/repoflow
+var (
+	buildVersion string
+	buildCommit  string
+	buildDate    string
+)
+
+// buildInfo is what's known about the build that's running
+type buildInfo struct {
+	Version   string
+	Commit    string
+	Date      string // When it was built if set by -ldflags, otherwise the time of the commit
+	Dirty     bool   // The working tree had changes which weren't committed when it was built
+	GoVersion string
+}
+
+// currentBuild reads the build information Go embedded, with anything set by -ldflags on top
+func currentBuild() buildInfo {
+	info, _ := debug.ReadBuildInfo()
+	build := readBuildInfo(info)
+
+	if buildVersion != "" {
+		build.Version = buildVersion
+	}
+	if buildCommit != "" {
+		build.Commit = buildCommit
+	}
+	if buildDate != "" {
+		build.Date = buildDate
+	}
+
+	return build
+}
+
+// readBuildInfo takes the module version and the VCS settings from the build information, which is nil when there isn't any
+func readBuildInfo(info *debug.BuildInfo) buildInfo {
+	build := buildInfo{Version: Version, GoVersion: runtime.Version()}
+	if info == nil {
+		return build
+	}
+
+	// go install ...@v1.2.3 and builds inside the repository give a version, go run and builds without VCS give (devel)
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		build.Version = info.Main.Version
+	}
+	if info.GoVersion != "" {
+		build.GoVersion = info.GoVersion
+	}
+
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			build.Commit = setting.Value
+		case "vcs.time":
+			build.Date = setting.Value
+		case "vcs.modified":
+			build.Dirty = setting.Value == "true"
+		}
+	}
+
+	return build
+}
+
+// printVersion prints the version, and with verbose everything needed to tell builds apart in a bug report
+func printVersion(verbose bool) {
+	build := currentBuild()
+
+	if !verbose {
+		fmt.Println(build.Version)
+		return
+	}
+
+	buildCommit := build.Commit
+	if buildCommit == "" {
+		buildCommit = "unknown"
+	}
+	if build.Dirty {
+		buildCommit += " (modified)"
+	}
+
+	buildDate := build.Date
+	if buildDate == "" {
+		buildDate = "unknown"
+	}
+
+	fmt.Printf("Version:    %s\n", build.Version)
+	fmt.Printf("Commit:     %s\n", buildCommit)
+	fmt.Printf("Date:       %s\n", buildDate)
+	fmt.Printf("Go:         %s %s/%s\n", build.GoVersion, runtime.GOOS, runtime.GOARCH)
+
+	fmt.Println("Backends:")
+	for _, backend := range backends() {
+		fmt.Printf("  %-8s  %s\n", backend[0], backend[1])
+	}
+}
+
+// backends reports the tools repoflow talks to and whether they're ready to use
+func backends() [][2]string {
+	gitStatus := "not found on PATH"
+	if out, err := exec.Command("git", "--version").Output(); err == nil {
+		gitStatus = strings.TrimSpace(strings.TrimPrefix(string(out), "git version"))
+	}
+
+	githubStatus := "no GH_PERSONAL_TOKEN, issues and releases are disabled"
+	if os.Getenv("GH_PERSONAL_TOKEN") != "" {
+		githubStatus = "GH_PERSONAL_TOKEN set"
+	}
+
+	return [][2]string{
+		{"git", gitStatus},
+		{"github", githubStatus},
+	}
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestReadBuildInfo(t *testing.T) {
+	t.Log("Testing readBuildInfo")
+
+	build := readBuildInfo(nil)
+	if build.Version != Version || build.Commit != "" {
+		t.Errorf("without build information got %+v", build)
+	}
+
+	info := &debug.BuildInfo{
+		GoVersion: "go1.24.3",
+		Main:      debug.Module{Path: "github.com/jonathon-chew/go-repoflow", Version: "v0.8.0"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs", Value: "git"},
+			{Key: "vcs.revision", Value: "3e852ae0123456789"},
+			{Key: "vcs.time", Value: "2026-10-01T12:00:00Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+
+	want := buildInfo{Version: "v0.8.0", Commit: "3e852ae0123456789", Date: "2026-10-01T12:00:00Z", Dirty: true, GoVersion: "go1.24.3"}
+	if build := readBuildInfo(info); build != want {
+		t.Errorf("got %+v, want %+v", build, want)
+	}
+
+	info.Main.Version = "(devel)"
+	if build := readBuildInfo(info); build.Version != Version {
+		t.Errorf("(devel) should be reported as %s, got %s", Version, build.Version)
+	}
+}
+
+func TestCurrentBuild(t *testing.T) {
+	t.Log("Testing currentBuild puts the -ldflags values over the build information")
+
+	saved := [3]string{buildVersion, buildCommit, buildDate}
+	t.Cleanup(func() { buildVersion, buildCommit, buildDate = saved[0], saved[1], saved[2] })
+
+	buildVersion, buildCommit, buildDate = "v1.2.3-4-g3e852ae-dirty", "3e852ae", "2026-10-19T12:00:00Z"
+
+	build := currentBuild()
+	if build.Version != buildVersion || build.Commit != buildCommit || build.Date != buildDate {
+		t.Errorf("got %+v, want the -ldflags version %s, commit %s and date %s", build, buildVersion, buildCommit, buildDate)
+	}
+}
@@ -0,0 +1,142 @@
+package commits
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Bump levels, in order of size
+const (
+	BumpNone  string = ""
+	BumpPatch string = "patch"
+	BumpMinor string = "minor"
+	BumpMajor string = "major"
+)
+
+// headerRE matches type(scope)!: description, https://www.conventionalcommits.org/en/v1.0.0/
+var headerRE = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()]*)\))?(!)?: (.+)$`)
+
+// footerRE matches a git trailer style footer, BREAKING CHANGE is the one token allowed a space
+var footerRE = regexp.MustCompile(`^(BREAKING CHANGE|BREAKING-CHANGE|[A-Za-z-]+)(?:: | #)(.*)$`)
+
+// Commit is a commit message read as a Conventional Commit
+type Commit struct {
+	Hash        string
+	Author      string
+	Email       string
+	Type        string // feat, fix, ... lower case, empty when the header isn't conventional
+	Scope       string
+	Breaking    bool
+	Description string // The header after the colon, or the whole subject when it isn't conventional
+	Body        string
+	Footers     map[string][]string
+}
+
+// Parse reads the commit message, the first line is the header and a trailing block of footers is split out of the body
+func Parse(message string) Commit {
+	var commit Commit
+
+	message = strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n"))
+	header, rest, _ := strings.Cut(message, "\n")
+	header = strings.TrimSpace(header)
+
+	if match := headerRE.FindStringSubmatch(header); match != nil {
+		commit.Type = strings.ToLower(match[1])
+		commit.Scope = match[2]
+		commit.Breaking = match[3] == "!"
+		commit.Description = strings.TrimSpace(match[4])
+	} else {
+		commit.Description = header
+	}
+
+	paragraphs := strings.Split(strings.TrimSpace(rest), "\n\n")
+
+	// Footers are only ever the last paragraph
+	last := paragraphs[len(paragraphs)-1]
+	if footers, ok := parseFooters(last); ok {
+		commit.Footers = footers
+		paragraphs = paragraphs[:len(paragraphs)-1]
+	}
+
+	commit.Body = strings.TrimSpace(strings.Join(paragraphs, "\n\n"))
+
+	if len(commit.Footers["BREAKING CHANGE"]) > 0 || len(commit.Footers["BREAKING-CHANGE"]) > 0 {
+		commit.Breaking = true
+	}
+
+	return commit
+}
+
+// parseFooters reads the paragraph as footers, only when its first line is one, so a wrapped footer value is kept together
+func parseFooters(paragraph string) (map[string][]string, bool) {
+	lines := strings.Split(paragraph, "\n")
+	if paragraph == "" || !footerRE.MatchString(lines[0]) {
+		return nil, false
+	}
+
+	footers := map[string][]string{}
+	var token string
+
+	for _, line := range lines {
+		if match := footerRE.FindStringSubmatch(line); match != nil {
+			token = match[1]
+			footers[token] = append(footers[token], strings.TrimSpace(match[2]))
+			continue
+		}
+
+		// A line that isn't a footer carries on the value of the one before
+		values := footers[token]
+		values[len(values)-1] = strings.TrimSpace(values[len(values)-1] + "\n" + line)
+	}
+
+	return footers, true
+}
+
+// Bump is the size of release the commit needs on its own
+func (c Commit) Bump() string {
+	switch {
+	case c.Breaking:
+		return BumpMajor
+	case c.Type == "feat":
+		return BumpMinor
+	case c.Type == "fix" || c.Type == "perf":
+		return BumpPatch
+	}
+	return BumpNone
+}
+
+func bumpRank(bump string) int {
+	switch bump {
+	case BumpPatch:
+		return 1
+	case BumpMinor:
+		return 2
+	case BumpMajor:
+		return 3
+	}
+	return 0
+}
+
+// NextBump picks the biggest bump the commits need and returns the commits that needed it.
+// Before 1.0.0 a breaking change only needs preOneBreakingBump, minor unless it's set to major.
+func NextBump(commits []Commit, preOne bool, preOneBreakingBump string) (string, []Commit) {
+	var bump string
+	var reasons []Commit
+
+	for _, commit := range commits {
+		commitBump := commit.Bump()
+		if commitBump == BumpMajor && preOne && preOneBreakingBump != BumpMajor {
+			commitBump = BumpMinor
+		}
+
+		switch {
+		case commitBump == BumpNone:
+		case bumpRank(commitBump) > bumpRank(bump):
+			bump, reasons = commitBump, []Commit{commit}
+		case commitBump == bump:
+			reasons = append(reasons, commit)
+		}
+	}
+
+	return bump, reasons
+}
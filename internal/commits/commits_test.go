@@ -0,0 +1,70 @@
+package commits
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseHeader(t *testing.T) {
+	t.Log("Testing Parse reads the Conventional Commit header")
+
+	commit := Parse("feat(cli)!: add the auto flag")
+	if commit.Type != "feat" || commit.Scope != "cli" || !commit.Breaking || commit.Description != "add the auto flag" {
+		t.Errorf("unexpected commit %+v", commit)
+	}
+
+	plain := Parse("Update README.md")
+	if plain.Type != "" || plain.Description != "Update README.md" || plain.Bump() != BumpNone {
+		t.Errorf("unexpected commit %+v", plain)
+	}
+}
+
+func TestParseFooters(t *testing.T) {
+	t.Log("Testing Parse reads footers and BREAKING CHANGE")
+
+	commit := Parse("fix: stop the crash\n\nThe parser read past the end.\n\nBREAKING CHANGE: tags must now be\nsemantic versions\nRefs #12")
+
+	if !commit.Breaking || commit.Bump() != BumpMajor {
+		t.Errorf("expected a breaking change, got %+v", commit)
+	}
+	if commit.Body != "The parser read past the end." {
+		t.Errorf("body was %q", commit.Body)
+	}
+	if !slices.Equal(commit.Footers["BREAKING CHANGE"], []string{"tags must now be\nsemantic versions"}) {
+		t.Errorf("breaking change footer was %q", commit.Footers["BREAKING CHANGE"])
+	}
+	if !slices.Equal(commit.Footers["Refs"], []string{"12"}) {
+		t.Errorf("refs footer was %q", commit.Footers["Refs"])
+	}
+}
+
+func TestNextBump(t *testing.T) {
+	t.Log("Testing NextBump picks the biggest bump and its reasons")
+
+	commits := []Commit{
+		Parse("fix: one"),
+		Parse("feat: two"),
+		Parse("docs: three"),
+		Parse("feat(api): four"),
+	}
+
+	bump, reasons := NextBump(commits, false, "")
+	if bump != BumpMinor || len(reasons) != 2 || reasons[1].Description != "four" {
+		t.Errorf("got %s because of %+v", bump, reasons)
+	}
+
+	breaking := append(commits, Parse("refactor!: five"))
+
+	if bump, _ := NextBump(breaking, false, ""); bump != BumpMajor {
+		t.Errorf("expected a major bump after 1.0.0, got %s", bump)
+	}
+	if bump, _ := NextBump(breaking, true, ""); bump != BumpMinor {
+		t.Errorf("expected a minor bump before 1.0.0, got %s", bump)
+	}
+	if bump, _ := NextBump(breaking, true, BumpMajor); bump != BumpMajor {
+		t.Errorf("expected a major bump before 1.0.0 when configured, got %s", bump)
+	}
+	if bump, _ := NextBump([]Commit{Parse("chore: tidy")}, false, ""); bump != BumpNone {
+		t.Errorf("expected no bump, got %s", bump)
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// FileName is the per repository settings file, read from the root of the repository
+const FileName string = ".repoflow.json"
+
+// Config holds the optional settings for a repository, every field can be left out of the file
+type Config struct {
+	// Assignees maps a git author email to the GitHub login that should be assigned new TODO issues
+	Assignees map[string]string `json:"assignees,omitempty"`
+
+	// DefaultAssignee is used when the author of a TODO can't be matched to a GitHub login
+	DefaultAssignee string `json:"default_assignee,omitempty"`
+
+	// PreOneBreakingBump is the bump a breaking change causes before 1.0.0 with tag --auto, minor unless set to major
+	PreOneBreakingBump string `json:"pre_1_0_breaking_bump,omitempty"`
+
+	// Modules maps the folder of a module to the prefix of its tags, such as services/api to services/api/.
+	// Folders with their own go.mod are found without being listed here
+	Modules map[string]string `json:"modules,omitempty"`
+
+	// Forge is the kind of site hosting origin, github, gitlab, bitbucket or gitea, for self-hosted sites it can't be told from the host
+	Forge string `json:"forge,omitempty"`
+
+	// VersionFiles are more files for --bump-files to write the new version into
+	VersionFiles []VersionFile `json:"version_files,omitempty"`
+}
+
+// VersionFile is a file with the version in it, found by the first group of the regular expression
+type VersionFile struct {
+	Path    string `json:"path"`
+	Pattern string `json:"pattern"`
+}
+
+// Load reads the settings file in the current directory, a missing file is not an error and returns the zero Config
+func Load() (Config, error) {
+	var config Config
+
+	fileContents, ErrReadingFile := os.ReadFile(FileName)
+	if errors.Is(ErrReadingFile, os.ErrNotExist) {
+		return config, nil
+	}
+	if ErrReadingFile != nil {
+		return config, ErrReadingFile
+	}
+
+	if err := json.Unmarshal(fileContents, &config); err != nil {
+		return config, fmt.Errorf("error unmarshalling %s: %w", FileName, err)
+	}
+
+	return config, nil
+}
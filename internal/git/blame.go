@@ -0,0 +1,156 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jonathon-chew/go-repoflow/internal/config"
+)
+
+// uncommittedSha is what git blame reports for lines that only exist in the working tree
+const uncommittedSha string = "0000000000000000000000000000000000000000"
+
+// BlameInfo is the author information git blame reports for a single line
+type BlameInfo struct {
+	Commit      string
+	Author      string
+	AuthorEmail string
+	AuthorTime  time.Time
+}
+
+// Uncommitted reports whether the line hasn't been committed yet
+func (b BlameInfo) Uncommitted() bool {
+	return b.Commit == uncommittedSha
+}
+
+var mentionRE = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.])@([A-Za-z0-9](?:[A-Za-z0-9-]{0,38}))`)
+
+// BlameLine runs git blame on a single line of a file, line numbers start at 1
+func BlameLine(file string, line int) (BlameInfo, error) {
+	blames, err := runBlame(file, "-L", fmt.Sprintf("%d,%d", line, line))
+	if err != nil {
+		return BlameInfo{}, err
+	}
+
+	if len(blames) == 0 {
+		return BlameInfo{}, fmt.Errorf("git blame returned nothing for %s:%d", file, line)
+	}
+
+	return blames[0], nil
+}
+
+// BlameFile runs git blame on the whole file, the result is indexed by line number - 1
+func BlameFile(file string) ([]BlameInfo, error) {
+	return runBlame(file)
+}
+
+func runBlame(file string, extraArguments ...string) ([]BlameInfo, error) {
+	arguments := append([]string{"blame", "--line-porcelain"}, extraArguments...)
+	cmd := exec.Command("git", append(arguments, "--", file)...)
+
+	var out bytes.Buffer
+	var stderr bytes.Buffer
+
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git blame failed for %s: %s", file, strings.TrimSpace(stderr.String()))
+	}
+
+	var blames []BlameInfo
+	var blame BlameInfo
+
+	scanner := bufio.NewScanner(&out)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+
+		// Every line of the file comes last, after a tab
+		if strings.HasPrefix(text, "\t") {
+			blames = append(blames, blame)
+			blame = BlameInfo{}
+			continue
+		}
+
+		// The header is the commit sha followed by the line numbers
+		if blame.Commit == "" {
+			blame.Commit = strings.Fields(text)[0]
+			continue
+		}
+
+		key, value, _ := strings.Cut(text, " ")
+		switch key {
+		case "author":
+			blame.Author = value
+		case "author-mail":
+			blame.AuthorEmail = strings.Trim(value, "<>")
+		case "author-time":
+			seconds, ErrConv := strconv.ParseInt(value, 10, 64)
+			if ErrConv == nil {
+				blame.AuthorTime = time.Unix(seconds, 0)
+			}
+		}
+	}
+
+	// Lines which haven't been committed belong to whoever is running the scan
+	var userEmail string
+	for index := range blames {
+		if !blames[index].Uncommitted() {
+			continue
+		}
+		if userEmail == "" {
+			configEmail, ErrGettingEmail := exec.Command("git", "config", "user.email").Output()
+			if ErrGettingEmail != nil {
+				break
+			}
+			userEmail = strings.TrimSpace(string(configEmail))
+		}
+		blames[index].AuthorEmail = userEmail
+	}
+
+	return blames, nil
+}
+
+// findMention returns the first @username mentioned in the text, or an empty string
+func findMention(text string) string {
+	match := mentionRE.FindStringSubmatch(text)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// ResolveAssignee picks the GitHub login a new TODO issue should be assigned to.
+// In order it uses an @username in the TODO text, the assignees table in the config, the GitHub
+// author of the blamed commit and finally the default assignee from the config.
+func ResolveAssignee(text, file string, line int, settings config.Config) string {
+	if mention := findMention(text); mention != "" {
+		return mention
+	}
+
+	blame, ErrBlame := BlameLine(file, line)
+	if ErrBlame != nil {
+		fmt.Printf("[WARNING]: %s\n", ErrBlame)
+		return settings.DefaultAssignee
+	}
+
+	if login, ok := settings.Assignees[blame.AuthorEmail]; ok && login != "" {
+		return login
+	}
+
+	if !blame.Uncommitted() {
+		login, ErrGettingLogin := GetCommitAuthorLogin(blame.Commit)
+		if ErrGettingLogin == nil && login != "" {
+			return login
+		}
+	}
+
+	return settings.DefaultAssignee
+}
@@ -0,0 +1,197 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jonathon-chew/go-repoflow/internal/config"
+	"github.com/jonathon-chew/go-repoflow/internal/semver"
+)
+
+// VersionFile is a file with the version written in it, which --bump-files updates before tagging
+type VersionFile struct {
+	Path      string
+	Pattern   *regexp.Regexp // The first group of each match is replaced with the new version
+	FirstOnly bool           // Only replace the first match, the rest are usually the versions of dependencies
+}
+
+// knownVersionFiles are updated whenever they're in the module's folder, without needing to be in .repoflow.json
+var knownVersionFiles = []VersionFile{
+	{Path: "package.json", Pattern: regexp.MustCompile(`(?m)^\s*"version"\s*:\s*"([^"]+)"`), FirstOnly: true},
+	{Path: "Cargo.toml", Pattern: regexp.MustCompile(`(?m)^version\s*=\s*"([^"]+)"`), FirstOnly: true},
+	{Path: "VERSION", Pattern: regexp.MustCompile(`^\s*(\S+)`), FirstOnly: true},
+}
+
+// FindVersionFiles returns the version_files from .repoflow.json belonging to the module, and the package.json, Cargo.toml and VERSION files in its folder
+func FindVersionFiles(settings config.Config, module Module) ([]VersionFile, error) {
+	var files []VersionFile
+
+	// The root doesn't own the files inside other modules
+	var otherModules []Module
+	if module.Path == "" {
+		modules, err := FindModules(settings)
+		if err != nil {
+			return nil, err
+		}
+		otherModules = modules[1:]
+	}
+
+	for _, configured := range settings.VersionFiles {
+		filePath := path.Clean(configured.Path)
+		if !inModule(filePath, module.Path) || slices.ContainsFunc(otherModules, func(other Module) bool { return inModule(filePath, other.Path) }) {
+			continue
+		}
+
+		pattern, err := regexp.Compile(configured.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("the pattern for %s in %s is not valid: %w", configured.Path, config.FileName, err)
+		}
+		if pattern.NumSubexp() < 1 {
+			return nil, fmt.Errorf("the pattern for %s in %s needs a group around the version", configured.Path, config.FileName)
+		}
+
+		files = append(files, VersionFile{Path: filePath, Pattern: pattern})
+	}
+
+	for _, known := range knownVersionFiles {
+		known.Path = path.Join(module.Path, known.Path)
+		if _, err := os.Stat(known.Path); err == nil {
+			files = append(files, known)
+		}
+	}
+
+	return files, nil
+}
+
+func inModule(filePath, modulePath string) bool {
+	return modulePath == "" || strings.HasPrefix(filePath, modulePath+"/")
+}
+
+// versionChange is a version file before and after the version was replaced
+type versionChange struct {
+	path   string
+	mode   os.FileMode
+	before []byte
+	after  []byte
+}
+
+// planVersionChanges works out the new contents of each file without writing anything, failing if a file no longer has its version in it.
+// A match keeps its v if it had one, so both "1.2.3" and "v1.2.3" are kept in the same style
+func planVersionChanges(files []VersionFile, version semver.Version) ([]versionChange, error) {
+	plain := version
+	plain.Prefix = ""
+
+	var changes []versionChange
+	for _, file := range files {
+		info, err := os.Stat(file.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		before, err := os.ReadFile(file.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		matches := file.Pattern.FindAllSubmatchIndex(before, -1)
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("the version pattern for %s doesn't match anything in it", file.Path)
+		}
+		if file.FirstOnly {
+			matches = matches[:1]
+		}
+
+		var after []byte
+		var last int
+		for _, match := range matches {
+			start, end := match[2], match[3]
+			if start < 0 {
+				continue
+			}
+
+			replacement := plain.String()
+			if strings.HasPrefix(string(before[start:end]), "v") {
+				replacement = "v" + replacement
+			}
+
+			after = append(after, before[last:start]...)
+			after = append(after, replacement...)
+			last = end
+		}
+		after = append(after, before[last:]...)
+
+		if string(after) == string(before) {
+			continue
+		}
+
+		changes = append(changes, versionChange{path: file.Path, mode: info.Mode().Perm(), before: before, after: after})
+	}
+
+	return changes, nil
+}
+
+// commitVersionChanges writes the files and commits them as chore(release), returning how to undo it all.
+// Nothing else can be staged, and the files can't have changes of their own, or they would end up in the release commit
+func commitVersionChanges(changes []versionChange, newTag string) (func() error, error) {
+	staged, err := runGit("diff", "--cached", "--name-only")
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(staged) != "" {
+		return nil, errors.New("there are staged changes, commit or unstage them so they aren't part of the release commit")
+	}
+
+	var paths []string
+	for _, change := range changes {
+		unstaged, err := HasUnstagedChanges(change.path)
+		if err != nil {
+			return nil, err
+		}
+		if unstaged {
+			return nil, fmt.Errorf("%s has changes which aren't committed, commit them before bumping the version in it", change.path)
+		}
+		paths = append(paths, change.path)
+	}
+
+	head, err := runGit("rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	head = strings.TrimSpace(head)
+
+	restore := func() error {
+		var errs []error
+		for _, change := range changes {
+			errs = append(errs, os.WriteFile(change.path, change.before, change.mode))
+		}
+		return errors.Join(errs...)
+	}
+
+	undo := func() error {
+		// Move back to the commit before the release, then put the files back as they were
+		_, ErrResetting := runGit("reset", "-q", "--soft", head)
+		_, ErrUnstaging := runGit(append([]string{"reset", "-q", "--"}, paths...)...)
+		return errors.Join(ErrResetting, ErrUnstaging, restore())
+	}
+
+	for _, change := range changes {
+		if err := os.WriteFile(change.path, change.after, change.mode); err != nil {
+			return nil, errors.Join(err, restore())
+		}
+	}
+
+	if _, err := runGit(append([]string{"add", "--"}, paths...)...); err != nil {
+		return nil, errors.Join(err, undo())
+	}
+
+	if _, err := runGit("commit", "-q", "-m", "chore(release): "+newTag); err != nil {
+		return nil, errors.Join(err, undo())
+	}
+
+	return undo, nil
+}
@@ -0,0 +1,196 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jonathon-chew/go-repoflow/internal/semver"
+)
+
+func TestPlanVersionChanges(t *testing.T) {
+	t.Log("Testing planVersionChanges")
+
+	t.Chdir(t.TempDir())
+
+	files := map[string]string{
+		"package.json": "{\n  \"name\": \"app\",\n  \"version\": \"1.2.3\",\n  \"dependencies\": {\n    \"version\": \"9.9.9\"\n  }\n}\n",
+		"VERSION":      "1.2.3\n",
+		"version.go":   "var Version string = \"v1.2.3\"\nvar Other string = \"v1.2.3\"\n",
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(name, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	versionFiles := append(withoutFiles(knownVersionFiles, "Cargo.toml"), VersionFile{Path: "version.go", Pattern: regexp.MustCompile(`string = "(v[^"]+)"`)})
+
+	version, _ := semver.Parse("v1.3.0")
+	changes, err := planVersionChanges(versionFiles, version)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"package.json": strings.Replace(files["package.json"], "1.2.3", "1.3.0", 1),
+		"VERSION":      "1.3.0\n",
+		"version.go":   "var Version string = \"v1.3.0\"\nvar Other string = \"v1.3.0\"\n",
+	}
+
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %d", len(want), len(changes))
+	}
+	for _, change := range changes {
+		if string(change.after) != want[change.path] {
+			t.Errorf("%s: got %q, want %q", change.path, change.after, want[change.path])
+		}
+	}
+
+	// A file that's lost its version stops the release
+	if _, err := planVersionChanges([]VersionFile{{Path: "VERSION", Pattern: regexp.MustCompile(`version = "(.+)"`)}}, version); err == nil {
+		t.Error("expected an error when the pattern doesn't match")
+	}
+}
+
+func TestMakeTagRollsBack(t *testing.T) {
+	t.Log("Testing makeTag undoes the release commit when the tag can't be made")
+
+	t.Chdir(t.TempDir())
+
+	for _, arguments := range [][]string{
+		{"init", "-q"},
+		{"config", "user.name", "Tester"},
+		{"config", "user.email", "tester@example.com"},
+	} {
+		if _, err := runGit(arguments...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.WriteFile("VERSION", []byte("1.0.0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, arguments := range [][]string{
+		{"add", "VERSION"},
+		{"commit", "-q", "-m", "first"},
+		{"tag", "-m", "taken", "v1.1.0"},
+	} {
+		if _, err := runGit(arguments...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before, _ := runGit("rev-parse", "HEAD")
+
+	options := TagOptions{Push: PushNever, VersionFiles: withoutFiles(knownVersionFiles, "package.json", "Cargo.toml")}
+	if err := makeTag("v1.1.0", "", options); err == nil {
+		t.Fatal("expected making a tag which already exists to fail")
+	}
+
+	after, _ := runGit("rev-parse", "HEAD")
+	if before != after {
+		t.Errorf("the release commit was left behind, HEAD moved from %s to %s", before, after)
+	}
+
+	contents, _ := os.ReadFile("VERSION")
+	if string(contents) != "1.0.0\n" {
+		t.Errorf("VERSION was left as %q", contents)
+	}
+
+	if status, _ := runGit("status", "--porcelain"); status != "" {
+		t.Errorf("the working tree was left with changes:\n%s", status)
+	}
+
+	// With a free tag the version is committed and tagged
+	if err := makeTag("v1.2.0", "", options); err != nil {
+		t.Fatal(err)
+	}
+	if subject, _ := runGit("log", "-1", "--format=%s", "v1.2.0"); strings.TrimSpace(subject) != "chore(release): v1.2.0" {
+		t.Errorf("v1.2.0 tagged %q", subject)
+	}
+}
+
+func TestMakeTagPushChoice(t *testing.T) {
+	t.Log("Testing makeTag won't wait on a prompt without a terminal, and what --no-push and --dry-run make")
+
+	t.Chdir(t.TempDir())
+
+	for _, arguments := range [][]string{
+		{"init", "-q"},
+		{"config", "user.name", "Tester"},
+		{"config", "user.email", "tester@example.com"},
+		{"commit", "-q", "--allow-empty", "-m", "first"},
+	} {
+		if _, err := runGit(arguments...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	stdin := os.Stdin
+	os.Stdin = devNull
+	t.Cleanup(func() { os.Stdin = stdin })
+
+	tagExists := func(tag string) bool {
+		_, err := runGit("rev-parse", "-q", "--verify", "refs/tags/"+tag)
+		return err == nil
+	}
+
+	// Asking is the default, which fails before anything is made
+	if err := makeTag("v1.0.0", "", TagOptions{}); !errors.Is(err, ErrNotInteractive) {
+		t.Errorf("expected ErrNotInteractive, got %v", err)
+	}
+	if tagExists("v1.0.0") {
+		t.Error("the tag was made before failing")
+	}
+
+	// A dry run never asks or changes anything, whatever it would do about pushing
+	for _, options := range []TagOptions{{DryRun: true}, {DryRun: true, Push: PushAlways}, {DryRun: true, Yes: true}} {
+		if err := makeTag("v1.0.0", "", options); err != nil {
+			t.Errorf("dry run %+v returned %v", options, err)
+		}
+	}
+	if tagExists("v1.0.0") {
+		t.Error("a dry run made the tag")
+	}
+
+	// --no-push answers the question, so the tag is made and left local
+	if err := makeTag("v1.0.0", "", TagOptions{Push: PushNever}); err != nil {
+		t.Fatal(err)
+	}
+	if !tagExists("v1.0.0") {
+		t.Error("--no-push didn't make the tag")
+	}
+
+	// --yes pushes to the remote, and there isn't one called nowhere so the tag is rolled back
+	if err := makeTag("v1.1.0", "", TagOptions{Yes: true, Remote: "nowhere"}); err == nil {
+		t.Error("expected pushing to a missing remote to fail")
+	}
+	if tagExists("v1.1.0") {
+		t.Error("the tag was left behind after the push failed")
+	}
+}
+
+func withoutFiles(files []VersionFile, names ...string) []VersionFile {
+	var kept []VersionFile
+	for _, file := range files {
+		keep := true
+		for _, name := range names {
+			if file.Path == name {
+				keep = false
+			}
+		}
+		if keep {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
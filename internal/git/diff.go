@@ -0,0 +1,171 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// AddedLine is a line that a diff adds, along with where it ends up in the new version of the file
+type AddedLine struct {
+	File string
+	Line int
+	Text string
+}
+
+// AddedLinesSince returns the lines added between the merge base of the ref and HEAD, and the working tree
+func AddedLinesSince(base string) ([]AddedLine, error) {
+	mergeBase, ErrMergeBase := runGit("merge-base", base, "HEAD")
+	if ErrMergeBase != nil {
+		return nil, ErrMergeBase
+	}
+
+	diff, ErrDiff := runGit("-c", "core.quotePath=false", "diff", "--no-color", "--no-ext-diff", "--unified=0", strings.TrimSpace(mergeBase))
+	if ErrDiff != nil {
+		return nil, ErrDiff
+	}
+
+	return parseAddedLines(diff), nil
+}
+
+// StagedAddedLines returns the lines added in the index, the text comes from the staged blobs rather than the working tree
+func StagedAddedLines() ([]AddedLine, error) {
+	diff, ErrDiff := runGit("-c", "core.quotePath=false", "diff", "--cached", "--no-color", "--no-ext-diff", "--unified=0")
+	if ErrDiff != nil {
+		return nil, ErrDiff
+	}
+
+	return parseAddedLines(diff), nil
+}
+
+// HasUnstagedChanges reports whether the file in the working tree differs from the index
+func HasUnstagedChanges(path string) (bool, error) {
+	cmd := exec.Command("git", "diff", "--quiet", "--", path)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err == nil {
+		return false, nil
+	}
+
+	// git diff --quiet exits with 1 when there are differences
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) && exitError.ExitCode() == 1 {
+		return true, nil
+	}
+
+	return false, fmt.Errorf("git diff failed for %s: %s", path, strings.TrimSpace(stderr.String()))
+}
+
+// StagedFile returns the contents of the file in the index, which is what the next commit will have
+func StagedFile(path string) ([]byte, error) {
+	contents, err := runGit("show", ":"+path)
+	return []byte(contents), err
+}
+
+// StageFile adds the file in the working tree to the index
+func StageFile(path string) error {
+	_, err := runGit("add", "--", path)
+	return err
+}
+
+// runGit runs git with the arguments in the current directory, returning stdout or an error carrying stderr
+func runGit(arguments ...string) (string, error) {
+	return runGitIn("", arguments...)
+}
+
+// runGitIn runs git in the directory, the current one when it's empty
+func runGitIn(directory string, arguments ...string) (string, error) {
+	cmd := exec.Command("git", arguments...)
+	cmd.Dir = directory
+
+	var out bytes.Buffer
+	var stderr bytes.Buffer
+
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %s", arguments[0], strings.TrimSpace(stderr.String()))
+	}
+
+	return out.String(), nil
+}
+
+// parseAddedLines reads a unified diff and keeps the lines starting with +
+func parseAddedLines(diff string) []AddedLine {
+	var added []AddedLine
+	var file string
+	var lineNumber int
+
+	// The file headers come between the diff line and the first hunk, so an added line starting with ++ isn't read as one
+	var inHeader bool
+
+	scanner := bufio.NewScanner(strings.NewReader(diff))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(text, "diff "):
+			inHeader = true
+		case inHeader && strings.HasPrefix(text, "+++ "):
+			file = strings.TrimPrefix(text[len("+++ "):], "b/")
+			if file == "/dev/null" {
+				file = ""
+			}
+		case strings.HasPrefix(text, "@@ "):
+			inHeader = false
+			// @@ -old,count +new,count @@
+			fields := strings.Fields(text)
+			if len(fields) < 3 {
+				continue
+			}
+			start, _, _ := strings.Cut(strings.TrimPrefix(fields[2], "+"), ",")
+			lineNumber, _ = strconv.Atoi(start)
+		case !inHeader && strings.HasPrefix(text, "+") && file != "":
+			added = append(added, AddedLine{File: file, Line: lineNumber, Text: text[1:]})
+			lineNumber++
+		}
+	}
+
+	return added
+}
+
+// LinguistGenerated returns the paths which .gitattributes marks as linguist-generated
+func LinguistGenerated(paths []string) (map[string]bool, error) {
+	generated := map[string]bool{}
+	if len(paths) == 0 {
+		return generated, nil
+	}
+
+	cmd := exec.Command("git", "check-attr", "-z", "--stdin", "linguist-generated")
+	cmd.Stdin = strings.NewReader(strings.Join(paths, "\x00") + "\x00")
+
+	var out bytes.Buffer
+	var stderr bytes.Buffer
+
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return generated, fmt.Errorf("git check-attr failed: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	// With -z the output is path, attribute, value repeated, each ending in NUL
+	fields := strings.Split(out.String(), "\x00")
+	for index := 0; index+2 < len(fields); index += 3 {
+		value := fields[index+2]
+		if value == "set" || value == "true" {
+			generated[fields[index]] = true
+		}
+	}
+
+	return generated, nil
+}
@@ -7,13 +7,14 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
 
 	aphrodite "github.com/jonathon-chew/Aphrodite"
 	utils "github.com/jonathon-chew/go-repoflow/internal/Utils"
+	"github.com/jonathon-chew/go-repoflow/internal/browser"
+	"github.com/jonathon-chew/go-repoflow/internal/semver"
 )
 
 var HTTPStatusResponseMeanings = map[string]string{
@@ -54,6 +55,43 @@ func GetRemoteOrigin() (string, error) {
 	return out.String(), nil
 }
 
+// WebURL turns a remote url, https or ssh, into the https address of the repository's web page
+func WebURL(remote string) string {
+	remote = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(remote), "/"), ".git")
+
+	// scp like ssh, git@github.com:owner/repo
+	if !strings.Contains(remote, "://") {
+		if at := strings.Index(remote, "@"); at >= 0 {
+			remote = remote[at+1:]
+		}
+		return "https://" + strings.Replace(remote, ":", "/", 1)
+	}
+
+	_, rest, _ := strings.Cut(remote, "://")
+
+	// Drop any user name or token
+	host, path, _ := strings.Cut(rest, "/")
+	if at := strings.LastIndex(host, "@"); at >= 0 {
+		host = host[at+1:]
+	}
+
+	// An ssh port isn't the web port
+	if strings.HasPrefix(remote, "ssh://") {
+		host, _, _ = strings.Cut(host, ":")
+	}
+
+	return "https://" + host + "/" + path
+}
+
+// GetRemoteWebURL returns the web page of the remote origin
+func GetRemoteWebURL() (string, error) {
+	remote, err := GetRemoteOrigin()
+	if err != nil {
+		return "", err
+	}
+	return WebURL(remote), nil
+}
+
 func FindGitFolder() bool {
 
 	directoryList := utils.MakeDirectoryList(utils.FindFilesInCurrentDirectory())
@@ -67,28 +105,23 @@ func FindGitFolder() bool {
 	return true
 }
 
-func OpenRemoteOrigin(place string) error {
-	url, ErrGetRemote := GetRemoteOrigin()
+// OpenRemoteOrigin opens the web page of the remote, or its pull or issues page, with the opener.
+// forge overrides the kind of site worked out from the remote, see RemotePages
+func OpenRemoteOrigin(place, forge string, opener browser.Opener) error {
+	pages, ErrGetRemote := RemotePages(forge)
 	if ErrGetRemote != nil {
 		return ErrGetRemote
 	}
 
-	url = strings.TrimSpace(url)
-
-	if strings.Contains(url, "github.com") && place != "" {
-		switch place {
-		case "pull":
-			url = url + "/pulls"
-		case "issues":
-			url = url + "/issues"
-		}
-	} else if place != "" {
-		return fmt.Errorf("[ERROR]: only github.com has been implimented so far")
+	url := pages.Base
+	switch place {
+	case "pull":
+		url = pages.Pulls()
+	case "issues":
+		url = pages.Issue(0)
 	}
 
-	cmd := exec.Command("open", url)
-
-	ErrRun := cmd.Run()
+	ErrRun := opener.Open(url)
 	if ErrRun != nil {
 		fmt.Printf("Error: %s\n", ErrRun)
 		return ErrRun
@@ -122,7 +155,98 @@ func getTags() (string, error) {
 	return versions, nil
 }
 
+// SortedVersionTags returns the semantic version tags with the prefix from lowest to highest precedence, and the tags that aren't semantic versions.
+// Use an empty prefix for the tags of the root module
+func SortedVersionTags(prefix string) ([]string, []string, error) {
+	versions, err := getTags()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	type parsedTag struct {
+		tag     string
+		version semver.Version
+	}
+
+	var parsedTags []parsedTag
+	var others []string
+
+	for _, tag := range strings.Split(versions, "\n") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		tagPrefix, version, ErrParsing := ParseModuleTag(tag)
+		if ErrParsing != nil {
+			others = append(others, tag)
+			continue
+		}
+		if tagPrefix != prefix {
+			continue
+		}
+		parsedTags = append(parsedTags, parsedTag{tag, version})
+	}
+
+	slices.SortStableFunc(parsedTags, func(a, b parsedTag) int { return semver.Compare(a.version, b.version) })
+
+	var sorted []string
+	for _, parsed := range parsedTags {
+		sorted = append(sorted, parsed.tag)
+	}
+
+	return sorted, others, nil
+}
+
+// ReleaseTags returns every tag which is a semantic version, whichever module it belongs to
+func ReleaseTags() ([]string, error) {
+	versions, err := getTags()
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	for _, tag := range strings.Split(versions, "\n") {
+		tag = strings.TrimSpace(tag)
+		if _, _, ErrParsing := ParseModuleTag(tag); tag != "" && ErrParsing == nil {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags, nil
+}
+
+// PreviousTag returns the semantic version tag of the same module just before the tag given, empty when it's the first
+func PreviousTag(tag string) (string, error) {
+	prefix, _, ErrParsing := ParseModuleTag(tag)
+	if ErrParsing != nil {
+		return "", ErrParsing
+	}
+
+	sorted, _, err := SortedVersionTags(prefix)
+	if err != nil {
+		return "", err
+	}
+
+	index := slices.Index(sorted, tag)
+	if index < 0 {
+		return "", fmt.Errorf("%s is not a semantic version tag", tag)
+	}
+
+	if index == 0 {
+		return "", nil
+	}
+
+	return sorted[index-1], nil
+}
+
+// GetLatestTag returns the tag of the root module with the highest version
 func GetLatestTag() (string, error) {
+	return LatestModuleTag("")
+}
+
+// LatestModuleTag returns the tag with the highest version out of those with the prefix, empty when there aren't any
+func LatestModuleTag(prefix string) (string, error) {
 
 	if !FindGitFolder() {
 		return "", fmt.Errorf("[Error]: Unable to find a git folder in the current directory")
@@ -138,65 +262,180 @@ func GetLatestTag() (string, error) {
 		return "", nil
 	}
 
-	versionList := strings.Split(versions, "\n")
-
-	// If the list is only 1 item long it's the biggest, so early return
-	if len(versionList) == 1 {
-		return versionList[0], nil
-	}
+	var latestVersion semver.Version
+	var latestTag string
+	var skipped int
 
-	var biggestMajor, biggestMinor, biggestPatch int
-	var latestVersion string
-
-	for _, version := range versionList {
-		version = strings.TrimSpace(version)
-		if version == "" {
+	for _, tag := range strings.Split(versions, "\n") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
 			continue
 		}
 
-		if len(version) < 4 {
+		tagPrefix, version, ErrParsing := ParseModuleTag(tag)
+		if ErrParsing != nil {
+			skipped++
 			continue
 		}
 
-		if !strings.Contains(version, ".") || !strings.HasPrefix(version, "v") {
-			fmt.Printf("[WARNING]: Skipping looking at tag %s, as doesn't follow the convention v.[0-9].[0-9].[0-9]\n", version)
+		// The tags of other modules aren't a problem
+		if tagPrefix != prefix {
 			continue
 		}
 
-		versionParts := strings.Split(version[1:], ".")
-		if len(versionParts) < 3 {
-			continue
+		// Check if this version is greater than the current latest
+		if latestTag == "" || semver.Compare(version, latestVersion) > 0 {
+			latestVersion = version
+			latestTag = tag
 		}
+	}
 
-		major, ErrMajorConv := strconv.Atoi(versionParts[0])
-		minor, ErrMinorConv := strconv.Atoi(versionParts[1])
-		patch, ErrPatchConv := strconv.Atoi(versionParts[2])
+	// One line on stderr, so the tag printed by --tags can still be read by scripts. Only the root warns, or every module would
+	if skipped > 0 && prefix == "" {
+		fmt.Fprintf(os.Stderr, "[WARNING]: Skipped %d tags which aren't semantic versions, repoflow tags list shows them\n", skipped)
+	}
 
-		if ErrMajorConv != nil || ErrMinorConv != nil || ErrPatchConv != nil {
-			fmt.Printf("[WARNING]: Skipping tag %s due to conversion error\n", version)
-			continue
+	return latestTag, nil
+}
+
+// PushChoice is whether a new tag gets pushed to the remote
+type PushChoice int
+
+const (
+	PushAsk PushChoice = iota // Ask, or push when TagOptions.Yes is set
+	PushAlways
+	PushNever
+)
+
+// ErrNotInteractive is returned instead of waiting on a prompt nobody can answer
+var ErrNotInteractive = errors.New("stdin is not a terminal so there is nobody to answer the question")
+
+// TagOptions changes how NewGitTag bumps the version and what it puts in the tag
+type TagOptions struct {
+	// Preid turns a major, minor or patch bump into the first pre-release of that version, and names the pre-release for prerelease (rc when empty)
+	Preid string
+
+	// Message writes the annotated tag message, such as a changelog, from the previous tag (empty for the first tag) to the new one.
+	// When it isn't set the message is "Release Version: X"
+	Message func(previousTag, newTag string) (string, error)
+
+	// Yes answers yes to the questions instead of asking them
+	Yes bool
+
+	// Push says whether to push the new tag, overriding Yes
+	Push PushChoice
+
+	// Remote is where the tag is pushed, origin when empty
+	Remote string
+
+	// DryRun prints the tag that would be made and pushed without changing anything
+	DryRun bool
+
+	// Module is the part of a monorepo to tag, the root when it's the zero Module
+	Module Module
+
+	// OnlyIfChanged skips the tag when nothing under the module's path changed since its latest tag
+	OnlyIfChanged bool
+
+	// Sign makes a signed tag with the key and gpg.format from the user's git config, GPG by default or SSH
+	Sign bool
+
+	// VersionFiles have the new version written into them and committed as chore(release) before tagging, see FindVersionFiles
+	VersionFiles []VersionFile
+}
+
+func makeTag(newTag, message string, options TagOptions) error {
+	remote := options.Remote
+	if remote == "" {
+		remote = "origin"
+	}
+
+	defaultMessage := "Release Version: " + strings.TrimPrefix(strings.TrimPrefix(newTag, options.Module.TagPrefix), "v")
+	if options.Module.Path != "" {
+		defaultMessage += " of " + options.Module.Path
+	}
+
+	arguments := []string{"tag", newTag, "-m", defaultMessage}
+
+	// Keep the lines of a custom message exactly, git would otherwise strip Markdown headings as comments
+	if message != "" {
+		arguments = []string{"tag", newTag, "--cleanup=verbatim", "-m", message}
+	}
+
+	if options.Sign {
+		arguments = slices.Insert(arguments, 1, "--sign")
+	}
+
+	_, newVersion, ErrParsing := ParseModuleTag(newTag)
+	if ErrParsing != nil {
+		return ErrParsing
+	}
+
+	// Work out every file's new contents first, so a file that's drifted stops the release before anything changes
+	changes, ErrPlanning := planVersionChanges(options.VersionFiles, newVersion)
+	if ErrPlanning != nil {
+		return ErrPlanning
+	}
+
+	if options.DryRun {
+		for _, change := range changes {
+			fmt.Printf("Would update the version in %s\n", change.path)
+		}
+		if len(changes) > 0 {
+			fmt.Printf("Would commit them as chore(release): %s\n", newTag)
 		}
 
-		// Check if this version is greater than the current latest
-		if major > biggestMajor ||
-			(major == biggestMajor && minor > biggestMinor) ||
-			(major == biggestMajor && minor == biggestMinor && patch > biggestPatch) {
-			biggestMajor = major
-			biggestMinor = minor
-			biggestPatch = patch
-			latestVersion = version
+		kind := "tag"
+		if options.Sign {
+			kind = "signed tag"
+		}
+		aphrodite.PrintInfo(fmt.Sprintf("Dry run, would make the %s %s with the message:\n", kind, newTag))
+		fmt.Println(arguments[len(arguments)-1])
+
+		switch {
+		case options.Push == PushNever:
+			fmt.Printf("Would not push %s\n", newTag)
+		case options.Push == PushAlways || options.Yes:
+			fmt.Printf("Would push %s to %s\n", newTag, remote)
+		default:
+			fmt.Printf("Would ask whether to push %s to %s\n", newTag, remote)
 		}
+		return nil
 	}
 
-	if latestVersion == "" {
-		return "", nil
+	// Decide about pushing before the tag is made, so a script without a terminal doesn't leave a tag half done
+	push := options.Push == PushAlways || (options.Push == PushAsk && options.Yes)
+	if options.Push == PushAsk && !options.Yes && !utils.StdinIsTerminal() {
+		return fmt.Errorf("%w, pass --yes, --push or --no-push to choose whether to push the tag", ErrNotInteractive)
 	}
 
-	return latestVersion, nil
-}
+	// Each step adds how to undo it, so if a later step fails the repository is left as it was
+	var undo []func() error
+	rollback := func(err error) error {
+		for index := len(undo) - 1; index >= 0; index-- {
+			if ErrUndoing := undo[index](); ErrUndoing != nil {
+				fmt.Printf("[ERROR]: Unable to roll back: %s\n", ErrUndoing)
+			}
+		}
+		if len(undo) > 0 {
+			aphrodite.PrintInfo("Rolled back the release\n")
+		}
+		return err
+	}
+
+	if len(changes) > 0 {
+		undoCommit, ErrCommitting := commitVersionChanges(changes, newTag)
+		if ErrCommitting != nil {
+			return ErrCommitting
+		}
+		undo = append(undo, undoCommit)
+
+		for _, change := range changes {
+			fmt.Printf("Updated the version in %s\n", change.path)
+		}
+	}
 
-func makeTag(newTag string) error {
-	cmd := exec.Command("git", "tag", newTag, "-m", "Release Version: "+strings.ReplaceAll(newTag, "v", ""))
+	cmd := exec.Command("git", arguments...)
 
 	var out bytes.Buffer
 	var stderr bytes.Buffer
@@ -207,26 +446,44 @@ func makeTag(newTag string) error {
 	err := cmd.Run()
 	if err != nil {
 		fmt.Printf("Error: %s\n", stderr.String())
-		return err
+		return rollback(err)
 	}
+	undo = append(undo, func() error {
+		_, ErrDeleting := runGit("tag", "-d", newTag)
+		return ErrDeleting
+	})
 
 	aphrodite.PrintInfo(fmt.Sprintf("New latest tag:%s\n", newTag))
 
-	aphrodite.PrintBold("Cyan", "Do you want to push the new tag to git?\n")
+	if options.Push == PushAsk && !options.Yes {
+		aphrodite.PrintBold("Cyan", fmt.Sprintf("Do you want to push the new tag to %s?\n", remote))
+
+		var userChoicePushToGit string
+		_, ErrGettingUserChioce := fmt.Scan(&userChoicePushToGit)
+		if ErrGettingUserChioce != nil {
+			return rollback(ErrGettingUserChioce)
+		}
 
-	var userChoicePushToGit string
-	_, ErrGettingUserChioce := fmt.Scan(&userChoicePushToGit)
-	if ErrGettingUserChioce != nil {
-		return ErrGettingUserChioce
+		push = userChoicePushToGit == "y" || userChoicePushToGit == "Y" || userChoicePushToGit == "yes" || userChoicePushToGit == "Yes" || userChoicePushToGit == "YES"
 	}
 
-	if userChoicePushToGit == "y" || userChoicePushToGit == "Y" || userChoicePushToGit == "yes" || userChoicePushToGit == "Yes" || userChoicePushToGit == "YES" {
+	if push {
 		aphrodite.PrintInfo("Pushing to remote git respository.\n")
-		// git push --tags --force-with-lease=false
-		tagPushCmd := exec.Command("git", "push", "--tags", "--force-with-lease=false")
+
+		// Only push the new tag, git push --tags would push every local tag too.
+		// The release commit goes with it, atomically so the remote never gets one without the other
+		pushArguments := []string{"push", remote, "refs/tags/" + newTag}
+		if len(changes) > 0 {
+			pushArguments = []string{"push", "--atomic", remote, "HEAD", "refs/tags/" + newTag}
+		}
+
+		tagPushCmd := exec.Command("git", pushArguments...)
+		stderr.Reset()
+		tagPushCmd.Stderr = &stderr
 		ErrPushingTags := tagPushCmd.Run()
 		if ErrPushingTags != nil {
-			return ErrPushingTags
+			fmt.Printf("Error: %s\n", stderr.String())
+			return rollback(ErrPushingTags)
 		}
 		aphrodite.PrintInfo("Successfully pushed.\n")
 	}
@@ -234,14 +491,68 @@ func makeTag(newTag string) error {
 	return nil
 }
 
-func NewGitTag(argument string) error {
-	version, ErrGetLatestTag := GetLatestTag()
+// BumpTypes are the ways NewGitTag can move the version on
+var BumpTypes = []string{"major", "minor", "patch", "prerelease", "release"}
+
+// BumpVersion works out the next version for the bump type, see NewGitTag for how preid is used
+func BumpVersion(currentVersion semver.Version, argument, preid string) (semver.Version, error) {
+	var newVersion semver.Version
+
+	switch argument {
+	case "major":
+		newVersion = currentVersion.BumpMajor()
+	case "minor":
+		newVersion = currentVersion.BumpMinor()
+	case "patch":
+		newVersion = currentVersion.BumpPatch()
+	case "prerelease":
+		if preid == "" {
+			preid = "rc"
+		}
+		newVersion = currentVersion.BumpPrerelease(preid)
+
+		// A new preid starts again at 1 on the same version, so it has to sort above the current one, beta.1 never follows rc.2
+		if semver.Compare(newVersion, currentVersion) <= 0 {
+			return newVersion, fmt.Errorf("%s would sort below %s, use a preid which sorts after %s or promote it with release first", newVersion, currentVersion, currentVersion.Prerelease[0])
+		}
+		return newVersion, nil
+	case "release":
+		if !currentVersion.IsPrerelease() {
+			return newVersion, fmt.Errorf("%s is not a pre-release, so there is nothing to promote", currentVersion)
+		}
+		return currentVersion.Release(), nil
+	default:
+		return newVersion, errors.New(argument + " was not recognised as a valid command")
+	}
+
+	if preid != "" {
+		newVersion = newVersion.WithPrerelease(preid)
+
+		// A pre-release bumps to its own release, so v1.5.0-rc.2 with patch and beta would be v1.5.0-beta.1, below rc.2
+		if semver.Compare(newVersion, currentVersion) <= 0 {
+			return newVersion, fmt.Errorf("%s would sort below %s, promote it with release first or bump a larger part of the version", newVersion, currentVersion)
+		}
+	}
+
+	return newVersion, nil
+}
+
+// NewGitTag bumps the latest tag of the module by major, minor, patch, prerelease or release and makes the new tag
+func NewGitTag(argument string, options TagOptions) error {
+	version, ErrGetLatestTag := LatestModuleTag(options.Module.TagPrefix)
 	if ErrGetLatestTag != nil {
 		return ErrGetLatestTag
 	}
 
 	if version == "" {
-		ErrMakingTag := makeTag("v0.1.0")
+		firstTag := options.Module.TagPrefix + "v0.1.0"
+
+		message, ErrWritingMessage := tagMessage(options, "", firstTag)
+		if ErrWritingMessage != nil {
+			return ErrWritingMessage
+		}
+
+		ErrMakingTag := makeTag(firstTag, message, options)
 		if ErrMakingTag != nil {
 			return ErrMakingTag
 		}
@@ -250,53 +561,55 @@ func NewGitTag(argument string) error {
 
 	fmt.Println("Current latest tag: ", version)
 
-	if argument != "major" && argument != "minor" && argument != "patch" {
+	if options.OnlyIfChanged {
+		changed, ErrCheckingChanges := ChangedSince(version, options.Module.Path)
+		if ErrCheckingChanges != nil {
+			return ErrCheckingChanges
+		}
+		if !changed {
+			aphrodite.PrintInfo(fmt.Sprintf("Nothing in %s has changed since %s, so it hasn't been tagged\n", options.Module.Name(), version))
+			return nil
+		}
+	}
+
+	if !slices.Contains(BumpTypes, argument) {
+		if options.Yes || options.DryRun || !utils.StdinIsTerminal() {
+			return fmt.Errorf("no bump type was given, pass one of %s", strings.Join(BumpTypes, ", "))
+		}
+
 		var userChoiceVersionUpdate string
 
-		fmt.Printf("Do you want to increase the major, minor or patch of the tag?\n")
+		fmt.Printf("Do you want to increase the major, minor or patch of the tag, or make a prerelease or release?\n")
 
 		_, ErrUserInput := fmt.Scanln(&userChoiceVersionUpdate)
 		if ErrUserInput != nil {
 			return ErrUserInput
 		}
-		if userChoiceVersionUpdate != "major" && userChoiceVersionUpdate != "minor" && userChoiceVersionUpdate != "patch" {
-			return fmt.Errorf("[ERROR]: user input was not major, minor or patch")
+		if !slices.Contains(BumpTypes, userChoiceVersionUpdate) {
+			return fmt.Errorf("[ERROR]: user input was not major, minor, patch, prerelease or release")
 		} else {
 			argument = userChoiceVersionUpdate
 		}
 	}
 
-	major, ErrMajorConv := strconv.Atoi(strings.Split(version[1:], ".")[0])
-	if ErrMajorConv != nil {
-		return ErrMajorConv
+	_, currentVersion, ErrParsing := ParseModuleTag(version)
+	if ErrParsing != nil {
+		return ErrParsing
 	}
 
-	minor, ErrMinorConv := strconv.Atoi(strings.Split(version[1:], ".")[1])
-	if ErrMinorConv != nil {
-		return ErrMinorConv
+	newVersion, ErrBumping := BumpVersion(currentVersion, argument, options.Preid)
+	if ErrBumping != nil {
+		return ErrBumping
 	}
 
-	patch, ErrPatchConv := strconv.Atoi(strings.Split(version[1:], ".")[2])
-	if ErrPatchConv != nil {
-		return ErrPatchConv
-	}
-	var newTag string
+	newTag := options.Module.TagPrefix + newVersion.String()
 
-	switch argument {
-	case "major":
-		newMajor := major + 1
-		newTag = fmt.Sprintf("v%d.%d.%d", newMajor, 0, 0)
-	case "minor":
-		newMinor := minor + 1
-		newTag = fmt.Sprintf("v%d.%d.%d", major, newMinor, 0)
-	case "patch":
-		newPatch := patch + 1
-		newTag = fmt.Sprintf("v%d.%d.%d", major, minor, newPatch)
-	default:
-		return errors.New(argument + " was not recognised as a valid command")
+	message, ErrWritingMessage := tagMessage(options, version, newTag)
+	if ErrWritingMessage != nil {
+		return ErrWritingMessage
 	}
 
-	ErrMakingTag := makeTag(newTag)
+	ErrMakingTag := makeTag(newTag, message, options)
 	if ErrMakingTag != nil {
 		return ErrMakingTag
 	}
@@ -304,51 +617,11 @@ func NewGitTag(argument string) error {
 	return nil
 }
 
-// Entry is the folder that you would like to check if their is an update to git in it.
-// Only does it in the root directory, if recusively going into folders it won't return false positives
-// The only time would be a submodule
-func CheckForGitUpdate(entry string) error {
-
-	currentWorkingDirectory, ErrGettingWorkingDirectory := os.Getwd()
-	if ErrGettingWorkingDirectory != nil {
-		return fmt.Errorf("getwd: %w", ErrGettingWorkingDirectory)
-	}
-
-	dirPath := filepath.Join(currentWorkingDirectory, entry)
-
-	// Fast repo check: is there a .git directory / file?
-	if _, err := os.Stat(filepath.Join(dirPath, ".git")); err != nil {
-		return errors.New("not a git folder") // not a git repo (or not accessible)
-	}
-
-	cmd := exec.Command("git", "status", "--porcelain")
-	cmd.Dir = dirPath
-
-	var out, stderr bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("git status failed in %s: %s", dirPath, stderr.String())
-	}
-
-	if out.Len() > 0 {
-		fmt.Printf("%s has a git update\n%s\n", entry, out.String())
-	}
-
-	aheadCmd := exec.Command("git", "rev-list", "--count", "@{u}..HEAD")
-	aheadCmd.Dir = dirPath
-
-	var aheadOut bytes.Buffer
-	aheadCmd.Stdout = &aheadOut
-
-	if err := aheadCmd.Run(); err == nil {
-		if strings.TrimSpace(aheadOut.String()) != "0" {
-			fmt.Printf("%s has commits to push\n", entry)
-		}
+func tagMessage(options TagOptions, previousTag, newTag string) (string, error) {
+	if options.Message == nil {
+		return "", nil
 	}
-
-	return nil
+	return options.Message(previousTag, newTag)
 }
 
 func MakeCommitMap(option string) {
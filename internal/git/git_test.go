@@ -3,9 +3,13 @@ package git
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 
 	aphrodite "github.com/jonathon-chew/Aphrodite"
+	"github.com/jonathon-chew/go-repoflow/internal/semver"
 )
 
 func TestRemoteURL(t *testing.T) {
@@ -41,48 +45,37 @@ func TestGitTag(t *testing.T) {
 	t.Log(returnString)
 }
 
-/* func TestHelpVersionMatchesLatestGitTag(t *testing.T) {
+func TestHelpVersionMatchesLatestGitTag(t *testing.T) {
 	t.Log("Testing whether the help function version matches the latest git tag")
 
-	_, ErrFindingFile := os.Stat("./internal/cli/cli.go")
-	if ErrFindingFile != nil {
-		t.Error(aphrodite.ReturnError("[Error]: Could not find the file"))
+	root, err := runGit("rev-parse", "--show-toplevel")
+	if err != nil {
+		t.Fatal(err)
 	}
+	t.Chdir(strings.TrimSpace(root))
 
-	fileContentsBytes, err := os.ReadFile("./internal/cli/cli.go")
+	actualTag, err := GetLatestTag()
 	if err != nil {
-		t.Error(aphrodite.ReturnError(fmt.Sprintf("There was an error opening the file %s", err)))
-		return
+		t.Fatal("Unable to get latest tag to compare")
+	}
+	if actualTag == "" {
+		t.Skip("There are no tags to compare the version with")
 	}
 
-	splitFile := strings.Split(string(fileContentsBytes), "\n")
-
-	for index, line := range splitFile {
-		if strings.TrimSpace(line) == `case "--version", "-version", "-v":` {
-
-			helpVersionLine := strings.TrimSpace(splitFile[index+1])
-			helpTag := strings.Replace(helpVersionLine, `fmt.Printf("`, "", 1)
-			helpTag = strings.Replace(helpTag, `\n")`, "", 1)
-
-			actualTag, err := GetLatestTag()
-			if err != nil {
-				t.Error("Unable to get latest tag to compare")
-			}
-
-			lineShouldBe := fmt.Sprintf(`fmt.Printf("%s\n")`, actualTag)
+	fileContentsBytes, err := os.ReadFile(filepath.Join("internal", "cli", "version.go"))
+	if err != nil {
+		t.Fatal(aphrodite.ReturnError(fmt.Sprintf("There was an error opening the file %s", err)))
+	}
 
-			if helpVersionLine == lineShouldBe {
-				t.Log("Versions match")
-				return
-			} else {
-				t.Errorf("Versions don't match Actual Tag: %s Version: %s", actualTag, helpTag)
-				return
-			}
-		}
+	match := regexp.MustCompile(`Version string = "(v[^"]+)"`).FindSubmatch(fileContentsBytes)
+	if match == nil {
+		t.Fatal("Unable to find a version line in the CMD")
 	}
 
-	t.Error("Unable to find a version line in the CMD")
-} */
+	if string(match[1]) != actualTag {
+		t.Errorf("Versions don't match Actual Tag: %s Version: %s, tag with --bump-files to keep them the same", actualTag, match[1])
+	}
+}
 
 func TestLatestGitTag(t *testing.T) {
 	t.Log("Testing GetLatestGitTag")
@@ -119,3 +112,221 @@ func TestGenericGit(t *testing.T) {
 
 	t.Logf("Owner: %s, Repo: %s, Token: %s", GitCredentials.Owner, GitCredentials.Repo, GitCredentials.Token)
 }
+
+func TestFindMention(t *testing.T) {
+	t.Log("Testing findMention")
+
+	cases := map[string]string{
+		"// TODO: ask @alice about the cache": "alice",
+		"// TODO: email bob@example.com":      "",
+		"// TODO: @bob-smith and @carol":      "bob-smith",
+		"// TODO: nobody mentioned":           "",
+	}
+
+	for text, want := range cases {
+		if got := findMention(text); got != want {
+			t.Errorf("findMention(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestParseAddedLines(t *testing.T) {
+	t.Log("Testing parseAddedLines")
+
+	diff := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -3,0 +4,2 @@ func main() {
++	first := 1
+++++ not a header
+@@ -10 +12 @@ func other() {
+-	old
++	new
+diff --git a/gone.go b/gone.go
+deleted file mode 100644
+--- a/gone.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package gone
+`
+
+	added := parseAddedLines(diff)
+	want := []AddedLine{
+		{File: "main.go", Line: 4, Text: "	first := 1"},
+		{File: "main.go", Line: 5, Text: "+++ not a header"},
+		{File: "main.go", Line: 12, Text: "	new"},
+	}
+
+	if len(added) != len(want) {
+		t.Fatalf("expected %d added lines, got %d: %v", len(want), len(added), added)
+	}
+
+	for index := range want {
+		if added[index] != want[index] {
+			t.Errorf("line %d: got %+v, want %+v", index, added[index], want[index])
+		}
+	}
+}
+
+func TestWebURL(t *testing.T) {
+	t.Log("Testing WebURL")
+
+	cases := map[string]string{
+		"https://github.com/jonathon-chew/go-repoflow.git\n":      "https://github.com/jonathon-chew/go-repoflow",
+		"https://token@github.com/jonathon-chew/go-repoflow":      "https://github.com/jonathon-chew/go-repoflow",
+		"git@github.com:jonathon-chew/go-repoflow.git":            "https://github.com/jonathon-chew/go-repoflow",
+		"ssh://git@gitlab.example.com:2222/group/sub/project.git": "https://gitlab.example.com/group/sub/project",
+	}
+
+	for remote, want := range cases {
+		if got := WebURL(remote); got != want {
+			t.Errorf("WebURL(%q) = %q, want %q", remote, got, want)
+		}
+	}
+}
+
+func TestParseModuleTag(t *testing.T) {
+	t.Log("Testing ParseModuleTag")
+
+	cases := []struct {
+		tag     string
+		prefix  string
+		version string
+	}{
+		{"v1.3.0", "", "v1.3.0"},
+		{"1.3.0-rc.1", "", "1.3.0-rc.1"},
+		{"services/api/v1.3.0", "services/api/", "v1.3.0"},
+		{"tools/v2.0.0-beta.1+build5", "tools/", "v2.0.0-beta.1+build5"},
+		{"api-v0.4.2", "api-", "v0.4.2"},
+	}
+
+	for _, c := range cases {
+		prefix, version, err := ParseModuleTag(c.tag)
+		if err != nil {
+			t.Errorf("ParseModuleTag(%q) returned %v", c.tag, err)
+			continue
+		}
+		if prefix != c.prefix || version.String() != c.version {
+			t.Errorf("ParseModuleTag(%q) = %q, %q, want %q, %q", c.tag, prefix, version, c.prefix, c.version)
+		}
+	}
+
+	for _, tag := range []string{"release", "services/api/latest", "v1.2"} {
+		if _, _, err := ParseModuleTag(tag); err == nil {
+			t.Errorf("ParseModuleTag(%q) should have failed", tag)
+		}
+	}
+}
+
+func TestBumpVersion(t *testing.T) {
+	t.Log("Testing BumpVersion only moves the version forward")
+
+	cases := []struct {
+		from     string
+		argument string
+		preid    string
+		want     string // empty when the bump should be refused
+	}{
+		{"v1.4.0", "minor", "", "v1.5.0"},
+		{"v1.4.0", "major", "beta", "v2.0.0-beta.1"},
+		{"v1.4.0", "prerelease", "", "v1.5.0-rc.1"},
+		{"v1.5.0-rc.1", "prerelease", "rc", "v1.5.0-rc.2"},
+		{"v1.5.0-beta.3", "prerelease", "rc", "v1.5.0-rc.1"},
+		{"v1.5.0-rc.2", "prerelease", "beta", ""},
+		{"v1.5.0-rc.2", "prerelease", "alpha", ""},
+		{"v1.5.0-rc.2", "patch", "", "v1.5.0"},
+		{"v1.5.0-rc.2", "patch", "beta", ""},
+		{"v1.5.0-rc.2", "major", "beta", "v2.0.0-beta.1"},
+		{"v1.5.0-rc.2", "release", "", "v1.5.0"},
+		{"v1.5.0", "release", "", ""},
+	}
+
+	for _, c := range cases {
+		version, err := semver.Parse(c.from)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := BumpVersion(version, c.argument, c.preid)
+		switch {
+		case c.want == "" && err == nil:
+			t.Errorf("BumpVersion(%s, %s, %q) = %s, want an error", c.from, c.argument, c.preid, got)
+		case c.want != "" && err != nil:
+			t.Errorf("BumpVersion(%s, %s, %q) returned %v", c.from, c.argument, c.preid, err)
+		case c.want != "" && got.String() != c.want:
+			t.Errorf("BumpVersion(%s, %s, %q) = %s, want %s", c.from, c.argument, c.preid, got, c.want)
+		}
+	}
+}
+
+// recordingOpener keeps the URLs it's asked to open
+type recordingOpener struct {
+	urls []string
+}
+
+func (r *recordingOpener) Open(url string) error {
+	r.urls = append(r.urls, url)
+	return nil
+}
+
+func TestOpenRemoteOrigin(t *testing.T) {
+	t.Log("Testing OpenRemoteOrigin with a fake opener")
+
+	t.Chdir(t.TempDir())
+	for _, arguments := range [][]string{
+		{"init", "-q"},
+		{"remote", "add", "origin", "git@github.com:jonathon-chew/go-repoflow.git"},
+	} {
+		if _, err := runGit(arguments...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opener := &recordingOpener{}
+	for _, place := range []string{"", "issues", "pull"} {
+		if err := OpenRemoteOrigin(place, "", opener); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{
+		"https://github.com/jonathon-chew/go-repoflow",
+		"https://github.com/jonathon-chew/go-repoflow/issues",
+		"https://github.com/jonathon-chew/go-repoflow/pulls",
+	}
+	if fmt.Sprint(opener.urls) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", opener.urls, want)
+	}
+}
+
+func TestStagedFile(t *testing.T) {
+	t.Log("Testing StagedFile reads the index rather than the working tree")
+
+	t.Chdir(t.TempDir())
+	if _, err := runGit("init", "-q"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile("main.go", []byte("// TODO: staged\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := StageFile("main.go"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("main.go", []byte("\x00 not staged\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	contents, err := StagedFile("main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(contents) != "// TODO: staged\n" {
+		t.Errorf("got %q", contents)
+	}
+
+	if _, err := StagedFile("missing.go"); err == nil {
+		t.Error("expected a file which isn't staged to fail")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"time"
 
 	utils "github.com/jonathon-chew/go-repoflow/internal/Utils"
 )
@@ -51,7 +52,21 @@ type Github_Issue struct {
 	Body      string   `json:"body"`
 	Milestone int      `json:"milestone,omitempty"`
 	Label     []string `json:"labels,omitempty"`
-	Assignees string   `json:"assignees,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+}
+
+type Github_Milestone struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	Due_on string `json:"due_on"`
+}
+
+type Github_Commit struct {
+	Sha    string `json:"sha"`
+	Author struct {
+		Login string `json:"login"`
+	} `json:"author"`
 }
 
 type Github_Label struct {
@@ -151,6 +166,76 @@ func conntactGithub[T any](websiteUrl string, token string) (T, error) {
 	return v, nil
 }
 
+// ErrGithubNotFound is returned by requestGithub for a 404, so callers can tell a missing resource from a failure
+var ErrGithubNotFound = errors.New("GitHub API error: 404 Not Found")
+
+// requestGithub sends the payload as JSON, or the raw bytes when it's an io.Reader, and decodes the response into T.
+// Any status other than 2xx is returned as an error, with ErrGithubNotFound for a 404.
+func requestGithub[T any](method, websiteUrl, token string, payload any) (T, error) {
+
+	var v T
+	var requestBody io.Reader
+	var contentType string = "application/json"
+
+	switch body := payload.(type) {
+	case nil:
+	case io.Reader:
+		requestBody = body
+		contentType = "application/octet-stream"
+	default:
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return v, err
+		}
+		requestBody = bytes.NewBuffer(jsonData)
+	}
+
+	request, err := http.NewRequest(method, websiteUrl, requestBody)
+	if err != nil {
+		return v, err
+	}
+
+	request.Header.Set("Accept", "application/vnd.github+json")
+	request.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	if requestBody != nil {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	client := http.Client{}
+
+	req, err := client.Do(request)
+	if err != nil {
+		return v, err
+	}
+
+	defer req.Body.Close()
+
+	responseBody, err := io.ReadAll(req.Body)
+	if err != nil {
+		return v, err
+	}
+
+	if req.StatusCode == http.StatusNotFound {
+		return v, ErrGithubNotFound
+	}
+
+	if req.StatusCode < 200 || req.StatusCode > 299 {
+		return v, fmt.Errorf("GitHub API error: %s %s", req.Status, strings.TrimSpace(string(responseBody)))
+	}
+
+	// Nothing comes back from a delete
+	if len(responseBody) == 0 || req.StatusCode == http.StatusNoContent {
+		return v, nil
+	}
+
+	if err := json.Unmarshal(responseBody, &v); err != nil {
+		return v, fmt.Errorf("error unmarshalling response: %w", err)
+	}
+
+	return v, nil
+}
+
 func GetRateLimit() (RateLimit, error) {
 
 	var rateLimit RateLimit
@@ -247,6 +332,11 @@ func ListGithubIssues(passedFromCLI bool) ([]GithubIssueResponse, error) {
 }
 
 func MakeGithubIssue(TITLE, BODY string) error {
+	return CreateGithubIssue(Github_Issue{Title: TITLE, Body: BODY})
+}
+
+// CreateGithubIssue posts the issue to the repository of the remote origin, including any assignees, labels or milestone set on it
+func CreateGithubIssue(issue Github_Issue) error {
 
 	// Get the credentials required
 	GithubCredentials, err := getGitCredentials()
@@ -254,11 +344,7 @@ func MakeGithubIssue(TITLE, BODY string) error {
 		return err
 	}
 
-	// Create the issue using a struct
-	issue := Github_Issue{
-		Title: strings.TrimSpace(TITLE),
-		Body:  BODY,
-	}
+	issue.Title = strings.TrimSpace(issue.Title)
 
 	// Convert the struct into JSON using the tags and Marshal
 	jsonData, err := json.Marshal(issue)
@@ -300,6 +386,62 @@ func MakeGithubIssue(TITLE, BODY string) error {
 	return nil
 }
 
+// GetCommitAuthorLogin asks GitHub which account authored the commit, this is empty when the commit email isn't linked to an account
+func GetCommitAuthorLogin(sha string) (string, error) {
+
+	GitCredentials, err := getGitCredentials()
+	if err != nil {
+		return "", err
+	}
+
+	commit, ErrContactingGithub := conntactGithub[Github_Commit](fmt.Sprintf("https://api.github.com/repos/%s/%s/commits/%s", GitCredentials.Owner, GitCredentials.Repo, sha), GitCredentials.Token)
+	if ErrContactingGithub != nil {
+		return "", ErrContactingGithub
+	}
+
+	return commit.Author.Login, nil
+}
+
+// FindMilestoneForDueDate returns the number of the open milestone with the earliest due date on or after the date given, 0 if none fit
+func FindMilestoneForDueDate(due time.Time) (int, error) {
+
+	GitCredentials, err := getGitCredentials()
+	if err != nil {
+		return 0, err
+	}
+
+	milestones, ErrContactingGithub := conntactGithub[[]Github_Milestone](fmt.Sprintf("https://api.github.com/repos/%s/%s/milestones?state=open&per_page=100", GitCredentials.Owner, GitCredentials.Repo), GitCredentials.Token)
+	if ErrContactingGithub != nil {
+		return 0, ErrContactingGithub
+	}
+
+	var chosenNumber int
+	var chosenDue time.Time
+
+	for _, milestone := range milestones {
+		if milestone.Due_on == "" {
+			continue
+		}
+
+		milestoneDue, ErrParsingDate := time.Parse(time.RFC3339, milestone.Due_on)
+		if ErrParsingDate != nil {
+			continue
+		}
+
+		// Compare whole days, GitHub stores the due date as a time on that day
+		milestoneDay := time.Date(milestoneDue.Year(), milestoneDue.Month(), milestoneDue.Day(), 0, 0, 0, 0, time.UTC)
+		if milestoneDay.Before(due) {
+			continue
+		}
+
+		if chosenNumber == 0 || milestoneDay.Before(chosenDue) {
+			chosenNumber, chosenDue = milestone.Number, milestoneDay
+		}
+	}
+
+	return chosenNumber, nil
+}
+
 // Get the github credentials based on the env variable for github, and the parsing of hte git remote
 func getGitCredentials() (Credentials, error) {
 
@@ -314,11 +456,15 @@ func getGitCredentials() (Credentials, error) {
 
 	if strings.Contains(remoteOrigin, "github") {
 
-		gitUrl := strings.ReplaceAll(remoteOrigin, ".git", "")
-		gitDetails := strings.Split(strings.ReplaceAll(gitUrl, "https://github.com/", ""), "/")
+		// WebURL handles ssh remotes as well as https ones, giving https://github.com/owner/repo
+		_, repoPath, _ := strings.Cut(strings.TrimPrefix(WebURL(remoteOrigin), "https://"), "/")
+		owner, repo, found := strings.Cut(repoPath, "/")
+		if !found || owner == "" || repo == "" {
+			return credentials, fmt.Errorf("unable to read the owner and repository from %s", strings.TrimSpace(remoteOrigin))
+		}
 
-		credentials.Owner = gitDetails[0]
-		credentials.Repo = strings.Replace(gitDetails[1], "\n", "", -1)
+		credentials.Owner = owner
+		credentials.Repo = repo
 		credentials.Token = os.Getenv("GH_PERSONAL_TOKEN")
 
 		if credentials.Token == "" {
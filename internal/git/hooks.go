@@ -0,0 +1,145 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hookMarker is the line that marks a hook as one written by repoflow
+const hookMarker string = "# Installed by repoflow, remove with: repoflow hooks uninstall"
+
+// chainedSuffix is added to the name of a hook that was in place before repoflow's, which is then run first
+const chainedSuffix string = ".repoflow-chained"
+
+// HookNames are the hooks repoflow can install
+var HookNames = []string{"pre-commit", "post-commit", "pre-push"}
+
+// HookStatus describes one hook in the hooks directory
+type HookStatus struct {
+	Name      string
+	Path      string
+	Installed bool // repoflow's hook is in place
+	Chained   bool // an earlier hook was kept and is run by repoflow's hook
+	Other     bool // there is a hook which repoflow didn't write
+}
+
+// HooksDirectory returns where git looks for hooks, following core.hooksPath
+func HooksDirectory() (string, error) {
+	directory, err := runGit("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Abs(strings.TrimSpace(directory))
+}
+
+// hookScript is the shell script for the hook, running any chained hook before the command
+func hookScript(name, command string) string {
+	var script strings.Builder
+
+	script.WriteString("#!/bin/sh\n")
+	script.WriteString(hookMarker + "\n")
+	script.WriteString(fmt.Sprintf("chained=\"$(dirname \"$0\")/%s%s\"\n", name, chainedSuffix))
+
+	// pre-push is given the refs being pushed on stdin, so both hooks need a copy
+	if name == "pre-push" {
+		script.WriteString("input=$(cat)\n")
+		script.WriteString("if [ -x \"$chained\" ]; then\n\tprintf '%s\\n' \"$input\" | \"$chained\" \"$@\" || exit $?\nfi\n")
+	} else {
+		script.WriteString("if [ -x \"$chained\" ]; then\n\t\"$chained\" \"$@\" || exit $?\nfi\n")
+	}
+
+	script.WriteString(command + "\n")
+
+	return script.String()
+}
+
+func isRepoflowHook(path string) bool {
+	contents, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(contents), hookMarker)
+}
+
+// InstallHook writes repoflow's hook, moving any existing hook aside so it still runs first
+func InstallHook(name, command string) error {
+	directory, err := HooksDirectory()
+	if err != nil {
+		return err
+	}
+
+	if ErrMakingDir := os.MkdirAll(directory, 0755); ErrMakingDir != nil {
+		return ErrMakingDir
+	}
+
+	path := filepath.Join(directory, name)
+
+	if _, ErrStat := os.Stat(path); ErrStat == nil && !isRepoflowHook(path) {
+		chainedPath := path + chainedSuffix
+		if _, ErrChainedStat := os.Stat(chainedPath); ErrChainedStat == nil {
+			return fmt.Errorf("both %s and %s exist, move one of them before installing", path, chainedPath)
+		}
+		if ErrRenaming := os.Rename(path, chainedPath); ErrRenaming != nil {
+			return ErrRenaming
+		}
+	}
+
+	return os.WriteFile(path, []byte(hookScript(name, command)), 0755)
+}
+
+// UninstallHook removes repoflow's hook and puts back any hook that was chained, it does nothing to other hooks
+func UninstallHook(name string) (bool, error) {
+	directory, err := HooksDirectory()
+	if err != nil {
+		return false, err
+	}
+
+	path := filepath.Join(directory, name)
+	if !isRepoflowHook(path) {
+		return false, nil
+	}
+
+	if ErrRemoving := os.Remove(path); ErrRemoving != nil {
+		return false, ErrRemoving
+	}
+
+	chainedPath := path + chainedSuffix
+	if _, ErrStat := os.Stat(chainedPath); ErrStat == nil {
+		if ErrRenaming := os.Rename(chainedPath, path); ErrRenaming != nil {
+			return true, ErrRenaming
+		}
+	}
+
+	return true, nil
+}
+
+// GetHookStatus reports the state of each of the hooks repoflow can install
+func GetHookStatus() ([]HookStatus, error) {
+	directory, err := HooksDirectory()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []HookStatus
+	for _, name := range HookNames {
+		status := HookStatus{Name: name, Path: filepath.Join(directory, name)}
+
+		_, ErrStat := os.Stat(status.Path)
+		switch {
+		case errors.Is(ErrStat, os.ErrNotExist):
+		case ErrStat != nil:
+			return nil, ErrStat
+		case isRepoflowHook(status.Path):
+			status.Installed = true
+			_, ErrChainedStat := os.Stat(status.Path + chainedSuffix)
+			status.Chained = ErrChainedStat == nil
+		default:
+			status.Other = true
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
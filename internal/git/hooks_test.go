@@ -0,0 +1,201 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// hookRepo makes an empty repository in a temporary directory and moves into it
+func hookRepo(t *testing.T) string {
+	t.Helper()
+
+	t.Chdir(t.TempDir())
+	if _, err := runGit("init", "-q"); err != nil {
+		t.Fatal(err)
+	}
+
+	directory, err := HooksDirectory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return directory
+}
+
+func TestHookScript(t *testing.T) {
+	t.Log("Testing hookScript runs the chained hook first and passes pre-push its stdin")
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is needed to run the hooks")
+	}
+
+	directory := t.TempDir()
+	output := filepath.Join(directory, "output")
+
+	// The chained hook records its arguments and stdin, then the command records that it ran after it
+	chained := "#!/bin/sh\necho \"chained $*\" >> " + output + "\ncat >> " + output + "\n"
+	if err := os.WriteFile(filepath.Join(directory, "pre-push"+chainedSuffix), []byte(chained), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	script := hookScript("pre-push", "echo command >> "+output)
+	if !strings.Contains(script, hookMarker) {
+		t.Errorf("the script is missing the marker:\n%s", script)
+	}
+
+	hook := filepath.Join(directory, "pre-push")
+	if err := os.WriteFile(hook, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	run := exec.Command(hook, "origin", "git@example.com:repo.git")
+	run.Stdin = strings.NewReader("refs/heads/main abc refs/heads/main def\n")
+	if out, err := run.CombinedOutput(); err != nil {
+		t.Fatalf("the hook failed: %v %s", err, out)
+	}
+
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "chained origin git@example.com:repo.git\nrefs/heads/main abc refs/heads/main def\ncommand\n"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// A failing chained hook stops the command from running
+	if err := os.WriteFile(filepath.Join(directory, "pre-commit"+chainedSuffix), []byte("#!/bin/sh\nexit 3\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	hook = filepath.Join(directory, "pre-commit")
+	if err := os.WriteFile(hook, []byte(hookScript("pre-commit", "echo ran >> "+output)), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	err = exec.Command(hook).Run()
+	if exitError, ok := err.(*exec.ExitError); !ok || exitError.ExitCode() != 3 {
+		t.Errorf("expected the hook to exit with 3, got %v", err)
+	}
+	if got, _ := os.ReadFile(output); strings.Contains(string(got), "ran") {
+		t.Error("the command ran after the chained hook failed")
+	}
+}
+
+func TestInstallHookChains(t *testing.T) {
+	t.Log("Testing InstallHook keeps an existing hook and UninstallHook puts it back")
+
+	directory := hookRepo(t)
+	path := filepath.Join(directory, "pre-commit")
+
+	existing := "#!/bin/sh\necho existing\n"
+	if err := os.WriteFile(path, []byte(existing), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InstallHook("pre-commit", "repoflow todos check --staged"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !isRepoflowHook(path) {
+		t.Error("repoflow's hook was not written")
+	}
+	chained, err := os.ReadFile(path + chainedSuffix)
+	if err != nil || string(chained) != existing {
+		t.Errorf("the existing hook was not chained, got %q %v", chained, err)
+	}
+
+	// Installing again replaces repoflow's hook without touching the chained one
+	if err := InstallHook("pre-commit", "repoflow todos check --staged"); err != nil {
+		t.Fatal(err)
+	}
+	if chained, _ := os.ReadFile(path + chainedSuffix); string(chained) != existing {
+		t.Errorf("installing again changed the chained hook to %q", chained)
+	}
+
+	removed, err := UninstallHook("pre-commit")
+	if err != nil || !removed {
+		t.Fatalf("expected the hook to be removed, got %v %v", removed, err)
+	}
+
+	restored, err := os.ReadFile(path)
+	if err != nil || string(restored) != existing {
+		t.Errorf("the existing hook was not restored, got %q %v", restored, err)
+	}
+	if _, err := os.Stat(path + chainedSuffix); !os.IsNotExist(err) {
+		t.Error("the chained hook was left behind")
+	}
+
+	// Hooks repoflow didn't write are left alone
+	removed, err = UninstallHook("pre-commit")
+	if err != nil || removed {
+		t.Errorf("expected another hook to be left alone, got %v %v", removed, err)
+	}
+	if restored, _ := os.ReadFile(path); string(restored) != existing {
+		t.Error("uninstalling changed a hook repoflow didn't write")
+	}
+}
+
+func TestInstallHookRefusesTwoHooks(t *testing.T) {
+	t.Log("Testing InstallHook won't overwrite a hook that is already chained")
+
+	directory := hookRepo(t)
+	path := filepath.Join(directory, "pre-push")
+
+	for _, file := range []string{path, path + chainedSuffix} {
+		if err := os.WriteFile(file, []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := InstallHook("pre-push", "repoflow --stage"); err == nil {
+		t.Error("expected an error when both hooks exist")
+	}
+	if isRepoflowHook(path) {
+		t.Error("the existing hook was overwritten")
+	}
+}
+
+func TestGetHookStatus(t *testing.T) {
+	t.Log("Testing GetHookStatus reports installed, chained and other hooks")
+
+	directory := hookRepo(t)
+
+	if err := os.WriteFile(filepath.Join(directory, "post-commit"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(directory, "pre-push"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := InstallHook("pre-commit", "repoflow todos check --staged"); err != nil {
+		t.Fatal(err)
+	}
+	if err := InstallHook("pre-push", "repoflow --stage"); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := GetHookStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []HookStatus{
+		{Name: "pre-commit", Installed: true},
+		{Name: "post-commit", Other: true},
+		{Name: "pre-push", Installed: true, Chained: true},
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("got %d statuses, want %d", len(statuses), len(want))
+	}
+
+	for index, expected := range want {
+		expected.Path = filepath.Join(directory, expected.Name)
+		if statuses[index] != expected {
+			t.Errorf("got %+v, want %+v", statuses[index], expected)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package git
+
+import (
+	"strings"
+	"time"
+)
+
+// LogEntry is one commit from git log
+type LogEntry struct {
+	Hash    string
+	Author  string
+	Email   string
+	Date    time.Time
+	Message string // The subject and body
+}
+
+// logFormat separates the fields with the unit separator and each commit with the record separator
+const logFormat string = "--format=%H%x1f%an%x1f%ae%x1f%aI%x1f%B%x1e"
+
+// CommitsBetween returns the commits reachable from to but not from, newest first.
+// An empty from gives every commit up to to, and an empty to means HEAD. Paths limit it to the commits that touched them.
+func CommitsBetween(from, to string, paths ...string) ([]LogEntry, error) {
+	if to == "" {
+		to = "HEAD"
+	}
+
+	revisionRange := to
+	if from != "" {
+		revisionRange = from + ".." + to
+	}
+
+	out, err := runGit(append([]string{"log", logFormat, revisionRange, "--"}, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLog(out), nil
+}
+
+func parseLog(out string) []LogEntry {
+	var entries []LogEntry
+
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.Split(strings.TrimLeft(record, "\n"), "\x1f")
+		if len(fields) != 5 {
+			continue
+		}
+
+		date, _ := time.Parse(time.RFC3339, fields[3])
+
+		entries = append(entries, LogEntry{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Email:   fields[2],
+			Date:    date,
+			Message: strings.TrimSpace(fields[4]),
+		})
+	}
+
+	return entries
+}
+
+// TagDate is when the tag was made, or the date of the commit for a lightweight tag
+func TagDate(tag string) (time.Time, error) {
+	out, err := runGit("for-each-ref", "--format=%(creatordate:iso-strict)", "refs/tags/"+tag)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if strings.TrimSpace(out) == "" {
+		// Not a tag, use the commit it points at instead
+		out, err = runGit("log", "-1", "--format=%cI", tag, "--")
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return time.Parse(time.RFC3339, strings.TrimSpace(out))
+}
@@ -0,0 +1,114 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/jonathon-chew/go-repoflow/internal/config"
+	"github.com/jonathon-chew/go-repoflow/internal/semver"
+)
+
+// Module is part of the repository which is versioned on its own, with tags such as services/api/v1.3.0
+type Module struct {
+	Path      string // The folder of the module from the root of the repository, empty for the root itself
+	TagPrefix string // What comes before the version in its tags, services/api/ for services/api/v1.3.0
+}
+
+// Name is how the module is shown, . for the root
+func (m Module) Name() string {
+	if m.Path == "" {
+		return "."
+	}
+	return m.Path
+}
+
+// FindModules returns the root module followed by the modules in .repoflow.json and the folders with their own go.mod, sorted by path.
+// Go modules are tagged with their folder as the prefix, the same as the go command expects
+func FindModules(settings config.Config) ([]Module, error) {
+	prefixes := map[string]string{}
+
+	goModFiles, err := runGit("ls-files", "--", "*go.mod")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range strings.Split(goModFiles, "\n") {
+		if path.Base(file) != "go.mod" || path.Dir(file) == "." {
+			continue
+		}
+		prefixes[path.Dir(file)] = path.Dir(file) + "/"
+	}
+
+	// The settings win over what the go.mod files say
+	for modulePath, prefix := range settings.Modules {
+		modulePath = strings.Trim(path.Clean(modulePath), "/")
+		if modulePath == "." || modulePath == "" {
+			continue
+		}
+		prefixes[modulePath] = prefix
+	}
+
+	modules := []Module{{}}
+	for modulePath, prefix := range prefixes {
+		modules = append(modules, Module{Path: modulePath, TagPrefix: prefix})
+	}
+
+	slices.SortFunc(modules[1:], func(a, b Module) int { return strings.Compare(a.Path, b.Path) })
+
+	return modules, nil
+}
+
+// FindModule returns the module at the path, which has to be the root or one of FindModules
+func FindModule(settings config.Config, modulePath string) (Module, error) {
+	modulePath = strings.Trim(path.Clean(modulePath), "/")
+	if modulePath == "." || modulePath == "" {
+		return Module{}, nil
+	}
+
+	modules, err := FindModules(settings)
+	if err != nil {
+		return Module{}, err
+	}
+
+	for _, module := range modules {
+		if module.Path == modulePath {
+			return module, nil
+		}
+	}
+
+	return Module{}, fmt.Errorf("%s has no go.mod and isn't in the modules of %s", modulePath, config.FileName)
+}
+
+// ParseModuleTag splits a tag such as services/api/v1.3.0 into its prefix and version
+func ParseModuleTag(tag string) (string, semver.Version, error) {
+	// The version starts at the beginning of the tag, or after a /, - or _
+	for index := range len(tag) {
+		if index > 0 && tag[index-1] != '/' && tag[index-1] != '-' && tag[index-1] != '_' {
+			continue
+		}
+
+		version, err := semver.Parse(tag[index:])
+		if err == nil {
+			return tag[:index], version, nil
+		}
+	}
+
+	_, err := semver.Parse(tag)
+	return "", semver.Version{}, err
+}
+
+// ChangedSince reports whether any files under the path have changed between the tag and HEAD
+func ChangedSince(tag, modulePath string) (bool, error) {
+	if modulePath == "" {
+		modulePath = "."
+	}
+
+	changed, err := runGit("diff", "--name-only", tag, "HEAD", "--", modulePath)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.TrimSpace(changed) != "", nil
+}
@@ -0,0 +1,252 @@
+package git
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Forge is the kind of site hosting the remote, they each lay out their pages differently
+type Forge string
+
+const (
+	ForgeGitHub    Forge = "github"
+	ForgeGitLab    Forge = "gitlab"
+	ForgeBitbucket Forge = "bitbucket"
+	ForgeGitea     Forge = "gitea" // Also Forgejo and Codeberg
+)
+
+// Forges are the sites Pages knows how to link to
+var Forges = []Forge{ForgeGitHub, ForgeGitLab, ForgeBitbucket, ForgeGitea}
+
+// DetectForge guesses the forge from the host of the web URL, GitHub when it can't tell.
+// Self-hosted sites without the name in the host need the forge setting in .repoflow.json
+func DetectForge(webURL string) Forge {
+	host := webURL
+	if parsed, err := url.Parse(webURL); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+	host = strings.ToLower(host)
+
+	switch {
+	case strings.Contains(host, "gitlab"):
+		return ForgeGitLab
+	case strings.Contains(host, "bitbucket"):
+		return ForgeBitbucket
+	case strings.Contains(host, "gitea"), strings.Contains(host, "forgejo"), strings.Contains(host, "codeberg"):
+		return ForgeGitea
+	}
+	return ForgeGitHub
+}
+
+// Pages builds the links to the pages of a repository
+type Pages struct {
+	Base  string // The web URL of the repository, such as https://github.com/jonathon-chew/go-repoflow
+	Forge Forge
+}
+
+// RemotePages is the Pages for the origin remote, forge overrides DetectForge when it isn't empty
+func RemotePages(forge string) (Pages, error) {
+	base, err := GetRemoteWebURL()
+	if err != nil {
+		return Pages{}, err
+	}
+
+	pages := Pages{Base: base, Forge: DetectForge(base)}
+	if forge != "" {
+		pages.Forge = Forge(strings.ToLower(forge))
+	}
+
+	for _, known := range Forges {
+		if pages.Forge == known {
+			return pages, nil
+		}
+	}
+	return pages, fmt.Errorf("%s is not a forge repoflow knows, use one of github, gitlab, bitbucket or gitea", forge)
+}
+
+// path joins the parts of a page onto the base, escaping each part of the file path
+func (p Pages) path(parts ...string) string {
+	var escaped []string
+	for _, part := range parts {
+		for _, segment := range strings.Split(part, "/") {
+			escaped = append(escaped, url.PathEscape(segment))
+		}
+	}
+	return p.Base + "/" + strings.Join(escaped, "/")
+}
+
+// lines is the anchor for a line or range of lines, empty when start is 0
+func (p Pages) lines(start, end int) string {
+	if start <= 0 {
+		return ""
+	}
+
+	switch p.Forge {
+	case ForgeGitLab:
+		if end > start {
+			return fmt.Sprintf("#L%d-%d", start, end)
+		}
+		return fmt.Sprintf("#L%d", start)
+	case ForgeBitbucket:
+		if end > start {
+			return fmt.Sprintf("#lines-%d:%d", start, end)
+		}
+		return fmt.Sprintf("#lines-%d", start)
+	default:
+		if end > start {
+			return fmt.Sprintf("#L%d-L%d", start, end)
+		}
+		return fmt.Sprintf("#L%d", start)
+	}
+}
+
+// File links to the file at the commit, highlighting the lines from start to end when start isn't 0
+func (p Pages) File(commit, file string, start, end int) string {
+	switch p.Forge {
+	case ForgeGitLab:
+		return p.path("-", "blob", commit, file) + p.lines(start, end)
+	case ForgeBitbucket:
+		return p.path("src", commit, file) + p.lines(start, end)
+	case ForgeGitea:
+		return p.path("src", "commit", commit, file) + p.lines(start, end)
+	default:
+		return p.path("blob", commit, file) + p.lines(start, end)
+	}
+}
+
+// Blame links to who last changed each line of the file at the commit
+func (p Pages) Blame(commit, file string, start, end int) string {
+	switch p.Forge {
+	case ForgeGitLab:
+		return p.path("-", "blame", commit, file) + p.lines(start, end)
+	case ForgeBitbucket:
+		return p.path("annotate", commit, file) + p.lines(start, end)
+	case ForgeGitea:
+		return p.path("blame", "commit", commit, file) + p.lines(start, end)
+	default:
+		return p.path("blame", commit, file) + p.lines(start, end)
+	}
+}
+
+// Commit links to the changes in a commit
+func (p Pages) Commit(commit string) string {
+	switch p.Forge {
+	case ForgeGitLab:
+		return p.path("-", "commit", commit)
+	case ForgeBitbucket:
+		return p.path("commits", commit)
+	default:
+		return p.path("commit", commit)
+	}
+}
+
+// Branch links to the files on a branch
+func (p Pages) Branch(branch string) string {
+	switch p.Forge {
+	case ForgeGitLab:
+		return p.path("-", "tree", branch)
+	case ForgeBitbucket:
+		return p.path("src", branch)
+	case ForgeGitea:
+		return p.path("src", "branch", branch)
+	default:
+		return p.path("tree", branch)
+	}
+}
+
+// Issue links to one issue, or the list of issues when number is 0
+func (p Pages) Issue(number int) string {
+	var parts []string
+	if p.Forge == ForgeGitLab {
+		parts = append(parts, "-")
+	}
+	parts = append(parts, "issues")
+	if number > 0 {
+		parts = append(parts, fmt.Sprint(number))
+	}
+	return p.path(parts...)
+}
+
+// Pulls links to the list of pull requests, merge requests on GitLab
+func (p Pages) Pulls() string {
+	switch p.Forge {
+	case ForgeGitLab:
+		return p.path("-", "merge_requests")
+	case ForgeBitbucket:
+		return p.path("pull-requests")
+	default:
+		return p.path("pulls")
+	}
+}
+
+// Compare links to the page for opening a pull request from head into base, merge requests on GitLab
+func (p Pages) Compare(base, head string) string {
+	switch p.Forge {
+	case ForgeGitLab:
+		query := url.Values{"merge_request[source_branch]": {head}, "merge_request[target_branch]": {base}}
+		return p.path("-", "merge_requests", "new") + "?" + query.Encode()
+	case ForgeBitbucket:
+		query := url.Values{"source": {head}, "dest": {base}}
+		return p.path("pull-requests", "new") + "?" + query.Encode()
+	case ForgeGitea:
+		return p.path("compare", base+"..."+head)
+	default:
+		return p.path("compare", base+"..."+head) + "?expand=1"
+	}
+}
+
+// Actions links to the CI runs, pipelines on GitLab and Bitbucket
+func (p Pages) Actions() string {
+	switch p.Forge {
+	case ForgeGitLab:
+		return p.path("-", "pipelines")
+	case ForgeBitbucket:
+		return p.path("pipelines")
+	default:
+		return p.path("actions")
+	}
+}
+
+// ResolveCommit gives the full hash of a ref such as HEAD, a branch or a short hash
+func ResolveCommit(ref string) (string, error) {
+	out, err := runGit("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("%s is not a commit", ref)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// CurrentBranch is the name of the checked out branch, an error when HEAD is detached
+func CurrentBranch() (string, error) {
+	out, err := runGit("symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("HEAD is not on a branch")
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// RepoRoot is the folder at the top of the working tree
+func RepoRoot() (string, error) {
+	out, err := runGit("rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// RepoPath turns a path from the current directory into a path from the root of the repository, as the forges use
+func RepoPath(file string) (string, error) {
+	prefix, err := runGit("rev-parse", "--show-prefix")
+	if err != nil {
+		return "", err
+	}
+
+	repoPath := path.Clean(path.Join(strings.TrimSpace(prefix), filepath.ToSlash(file)))
+	if repoPath == "." || strings.HasPrefix(repoPath, "../") {
+		return "", fmt.Errorf("%s is not inside the repository", file)
+	}
+	return repoPath, nil
+}
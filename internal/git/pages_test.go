@@ -0,0 +1,80 @@
+package git
+
+import "testing"
+
+func TestPages(t *testing.T) {
+	t.Log("Testing the page links for each forge")
+
+	const commit = "3e852ae"
+
+	cases := []struct {
+		forge Forge
+		base  string
+		want  []string
+	}{
+		{ForgeGitHub, "https://github.com/o/r", []string{
+			"https://github.com/o/r/blob/3e852ae/cmd/my%20app/main.go#L10-L20",
+			"https://github.com/o/r/blame/3e852ae/cmd/my%20app/main.go#L10",
+			"https://github.com/o/r/commit/3e852ae",
+			"https://github.com/o/r/tree/feature/login",
+			"https://github.com/o/r/issues/12",
+			"https://github.com/o/r/pulls",
+			"https://github.com/o/r/actions",
+			"https://github.com/o/r/compare/main...feature/login?expand=1",
+		}},
+		{ForgeGitLab, "https://gitlab.com/group/sub/r", []string{
+			"https://gitlab.com/group/sub/r/-/blob/3e852ae/cmd/my%20app/main.go#L10-20",
+			"https://gitlab.com/group/sub/r/-/blame/3e852ae/cmd/my%20app/main.go#L10",
+			"https://gitlab.com/group/sub/r/-/commit/3e852ae",
+			"https://gitlab.com/group/sub/r/-/tree/feature/login",
+			"https://gitlab.com/group/sub/r/-/issues/12",
+			"https://gitlab.com/group/sub/r/-/merge_requests",
+			"https://gitlab.com/group/sub/r/-/pipelines",
+			"https://gitlab.com/group/sub/r/-/merge_requests/new?merge_request%5Bsource_branch%5D=feature%2Flogin&merge_request%5Btarget_branch%5D=main",
+		}},
+		{ForgeBitbucket, "https://bitbucket.org/o/r", []string{
+			"https://bitbucket.org/o/r/src/3e852ae/cmd/my%20app/main.go#lines-10:20",
+			"https://bitbucket.org/o/r/annotate/3e852ae/cmd/my%20app/main.go#lines-10",
+			"https://bitbucket.org/o/r/commits/3e852ae",
+			"https://bitbucket.org/o/r/src/feature/login",
+			"https://bitbucket.org/o/r/issues/12",
+			"https://bitbucket.org/o/r/pull-requests",
+			"https://bitbucket.org/o/r/pipelines",
+			"https://bitbucket.org/o/r/pull-requests/new?dest=main&source=feature%2Flogin",
+		}},
+		{ForgeGitea, "https://codeberg.org/o/r", []string{
+			"https://codeberg.org/o/r/src/commit/3e852ae/cmd/my%20app/main.go#L10-L20",
+			"https://codeberg.org/o/r/blame/commit/3e852ae/cmd/my%20app/main.go#L10",
+			"https://codeberg.org/o/r/commit/3e852ae",
+			"https://codeberg.org/o/r/src/branch/feature/login",
+			"https://codeberg.org/o/r/issues/12",
+			"https://codeberg.org/o/r/pulls",
+			"https://codeberg.org/o/r/actions",
+			"https://codeberg.org/o/r/compare/main...feature/login",
+		}},
+	}
+
+	for _, c := range cases {
+		if detected := DetectForge(c.base); detected != c.forge {
+			t.Errorf("DetectForge(%q) = %s, want %s", c.base, detected, c.forge)
+		}
+
+		pages := Pages{Base: c.base, Forge: c.forge}
+		got := []string{
+			pages.File(commit, "cmd/my app/main.go", 10, 20),
+			pages.Blame(commit, "cmd/my app/main.go", 10, 10),
+			pages.Commit(commit),
+			pages.Branch("feature/login"),
+			pages.Issue(12),
+			pages.Pulls(),
+			pages.Actions(),
+			pages.Compare("main", "feature/login"),
+		}
+
+		for index := range c.want {
+			if got[index] != c.want[index] {
+				t.Errorf("%s: got %s, want %s", c.forge, got[index], c.want[index])
+			}
+		}
+	}
+}
@@ -0,0 +1,465 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+)
+
+type Github_Pull_Request struct {
+	Title string `json:"title"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Body  string `json:"body"`
+	Draft bool   `json:"draft"`
+}
+
+type Github_Pull_Ref struct {
+	Label string `json:"label"` // owner:branch
+	Ref   string `json:"ref"`
+	Sha   string `json:"sha"`
+	Repo  struct {
+		Full_name string `json:"full_name"`
+		Clone_url string `json:"clone_url"`
+	} `json:"repo"`
+}
+
+type Github_Pull struct {
+	Number              int               `json:"number"`
+	Title               string            `json:"title"`
+	Body                string            `json:"body"`
+	State               string            `json:"state"`
+	Draft               bool              `json:"draft"`
+	Merged              bool              `json:"merged"`
+	Html_url            string            `json:"html_url"`
+	User                Github_Assignee   `json:"user"`
+	Head                Github_Pull_Ref   `json:"head"`
+	Base                Github_Pull_Ref   `json:"base"`
+	Requested_reviewers []Github_Assignee `json:"requested_reviewers"`
+	Created_at          string            `json:"created_at"`
+	Updated_at          string            `json:"updated_at"`
+	// Only filled in for a single pull request, not the list
+	Commits       int `json:"commits"`
+	Additions     int `json:"additions"`
+	Deletions     int `json:"deletions"`
+	Changed_files int `json:"changed_files"`
+}
+
+// CreatePullRequest opens the pull request on the repository of the remote origin, then asks the reviewers and adds the labels
+func CreatePullRequest(pull Github_Pull_Request, reviewers, labels []string) (Github_Pull, error) {
+	repoUrl, GitCredentials, err := githubRepo()
+	if err != nil {
+		return Github_Pull{}, err
+	}
+
+	return createPull(repoUrl, GitCredentials.Token, pull, reviewers, labels)
+}
+
+func createPull(repoUrl, token string, pull Github_Pull_Request, reviewers, labels []string) (Github_Pull, error) {
+	created, err := requestGithub[Github_Pull]("POST", repoUrl+"/pulls", token, pull)
+	if err != nil {
+		return created, err
+	}
+
+	// The pull request is already made, so these are warnings rather than failures
+	if len(reviewers) > 0 {
+		payload := map[string][]string{"reviewers": reviewers}
+		if _, err := requestGithub[Github_Pull]("POST", fmt.Sprintf("%s/pulls/%d/requested_reviewers", repoUrl, created.Number), token, payload); err != nil {
+			aphrodite.PrintWarning(fmt.Sprintf("Unable to request reviews from %s: %s\n", strings.Join(reviewers, ", "), err))
+		}
+	}
+
+	if len(labels) > 0 {
+		payload := map[string][]string{"labels": labels}
+		if _, err := requestGithub[[]struct{}]("POST", fmt.Sprintf("%s/issues/%d/labels", repoUrl, created.Number), token, payload); err != nil {
+			aphrodite.PrintWarning(fmt.Sprintf("Unable to add the labels %s: %s\n", strings.Join(labels, ", "), err))
+		}
+	}
+
+	return created, nil
+}
+
+// DefaultBranch is the branch the remote's HEAD points at, main when the remote HEAD isn't known locally
+func DefaultBranch(remote string) string {
+	out, err := runGit("symbolic-ref", "--quiet", "--short", "refs/remotes/"+remote+"/HEAD")
+	if err != nil || strings.TrimSpace(out) == "" {
+		return "main"
+	}
+	return strings.TrimPrefix(strings.TrimSpace(out), remote+"/")
+}
+
+// PushBranch pushes the branch if the remote doesn't have all of it, setting the upstream the first time.
+// It reports whether anything was pushed
+func PushBranch(remote, branch string) (bool, error) {
+	upstream, err := runGit("rev-parse", "--abbrev-ref", "--symbolic-full-name", branch+"@{upstream}")
+	if err != nil || strings.TrimSpace(upstream) == "" {
+		if _, err := runGit("push", "--set-upstream", remote, branch); err != nil {
+			return false, fmt.Errorf("unable to push %s to %s: %w", branch, remote, err)
+		}
+		return true, nil
+	}
+
+	ahead, err := runGit("rev-list", "--count", strings.TrimSpace(upstream)+".."+branch)
+	if err != nil {
+		return false, err
+	}
+	if count, _ := strconv.Atoi(strings.TrimSpace(ahead)); count == 0 {
+		return false, nil
+	}
+
+	if _, err := runGit("push", remote, branch); err != nil {
+		return false, fmt.Errorf("unable to push %s to %s: %w", branch, remote, err)
+	}
+	return true, nil
+}
+
+type Github_Review struct {
+	User  Github_Assignee `json:"user"`
+	State string          `json:"state"` // APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED or PENDING
+}
+
+type Github_Commit_Status struct {
+	Context    string `json:"context"`
+	State      string `json:"state"` // error, failure, pending or success
+	Target_url string `json:"target_url"`
+}
+
+type Github_Combined_Status struct {
+	State    string                 `json:"state"`
+	Statuses []Github_Commit_Status `json:"statuses"`
+}
+
+type Github_Check_Run struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`     // queued, in_progress or completed
+	Conclusion string `json:"conclusion"` // Set once it's completed, such as success or failure
+	Html_url   string `json:"html_url"`
+}
+
+type Github_Check_Runs struct {
+	Total_count int                `json:"total_count"`
+	Check_runs  []Github_Check_Run `json:"check_runs"`
+}
+
+// The states of a check, and of all the checks together
+const (
+	CheckPassing string = "passing"
+	CheckFailing string = "failing"
+	CheckPending string = "pending"
+	CheckSkipped string = "skipped"
+)
+
+// The review states of a pull request, from the latest review of each reviewer
+const (
+	ReviewApproved         string = "approved"
+	ReviewChangesRequested string = "changes requested"
+	ReviewRequired         string = "review required"
+)
+
+// Check is one commit status or check run
+type Check struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+	URL   string `json:"url,omitempty"`
+}
+
+// Checks are the statuses and check runs on the head of a pull request
+type Checks struct {
+	State   string  `json:"state"` // Failing if any are, pending if any are still running, passing if any passed, empty when there aren't any
+	Passing int     `json:"passing"`
+	Failing int     `json:"failing"`
+	Pending int     `json:"pending"`
+	Checks  []Check `json:"checks"`
+}
+
+// PullStatus is a pull request with its review state and the checks on its head
+type PullStatus struct {
+	Github_Pull
+	Review string `json:"review"`
+	Checks Checks `json:"checks"`
+}
+
+// githubRepo is the API URL of the remote origin's repository, with the credentials to use it
+func githubRepo() (string, Credentials, error) {
+	GitCredentials, err := getGitCredentials()
+	if err != nil {
+		return "", GitCredentials, err
+	}
+
+	return fmt.Sprintf("https://api.github.com/repos/%s/%s", GitCredentials.Owner, GitCredentials.Repo), GitCredentials, nil
+}
+
+// ListPullRequests returns the open pull requests, newest first, with their reviews and checks.
+// Only the pull requests keep returns true for have their reviews and checks fetched and are returned, keep can be nil to return all of them
+func ListPullRequests(keep func(Github_Pull) bool) ([]PullStatus, error) {
+	repoUrl, GitCredentials, err := githubRepo()
+	if err != nil {
+		return nil, err
+	}
+
+	pulls, err := listPulls(repoUrl, GitCredentials.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if keep != nil {
+		pulls = slices.DeleteFunc(pulls, func(pull Github_Pull) bool { return !keep(pull) })
+	}
+
+	return pullStatuses(repoUrl, GitCredentials.Token, pulls)
+}
+
+// pullsPerPage is the most GitHub gives in one page
+const pullsPerPage int = 100
+
+// listPulls reads every page of the open pull requests
+func listPulls(repoUrl, token string) ([]Github_Pull, error) {
+	var pulls []Github_Pull
+
+	for page := 1; ; page++ {
+		pagePulls, err := requestGithub[[]Github_Pull]("GET", fmt.Sprintf("%s/pulls?state=open&per_page=%d&page=%d", repoUrl, pullsPerPage, page), token, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		pulls = append(pulls, pagePulls...)
+		if len(pagePulls) < pullsPerPage {
+			return pulls, nil
+		}
+	}
+}
+
+// GetPullRequest returns one pull request, with its diff stats, reviews and checks
+func GetPullRequest(number int) (PullStatus, error) {
+	repoUrl, GitCredentials, err := githubRepo()
+	if err != nil {
+		return PullStatus{}, err
+	}
+
+	pull, err := requestGithub[Github_Pull]("GET", fmt.Sprintf("%s/pulls/%d", repoUrl, number), GitCredentials.Token, nil)
+	if errors.Is(err, ErrGithubNotFound) {
+		return PullStatus{}, fmt.Errorf("there's no pull request #%d", number)
+	}
+	if err != nil {
+		return PullStatus{}, err
+	}
+
+	statuses, err := pullStatuses(repoUrl, GitCredentials.Token, []Github_Pull{pull})
+	if err != nil {
+		return PullStatus{}, err
+	}
+	return statuses[0], nil
+}
+
+// BranchPullRequest is the number of the open pull request from the branch, 0 when there isn't one
+func BranchPullRequest(branch string) (int, error) {
+	repoUrl, GitCredentials, err := githubRepo()
+	if err != nil {
+		return 0, err
+	}
+
+	head := url.QueryEscape(GitCredentials.Owner + ":" + branch)
+	pulls, err := requestGithub[[]Github_Pull]("GET", repoUrl+"/pulls?state=open&head="+head, GitCredentials.Token, nil)
+	if err != nil || len(pulls) == 0 {
+		return 0, err
+	}
+	return pulls[0].Number, nil
+}
+
+// GithubLogin is the login of the user GH_PERSONAL_TOKEN belongs to
+func GithubLogin() (string, error) {
+	GitCredentials, err := getGitCredentials()
+	if err != nil {
+		return "", err
+	}
+
+	user, err := requestGithub[Github_Assignee]("GET", "https://api.github.com/user", GitCredentials.Token, nil)
+	return user.Login, err
+}
+
+// pullStatusLimit is how many pull requests have their reviews and checks fetched at once
+const pullStatusLimit int = 8
+
+// pullStatuses fetches the reviews and checks of the pull requests, several at a time, keeping them in order
+func pullStatuses(repoUrl, token string, pulls []Github_Pull) ([]PullStatus, error) {
+	statuses := make([]PullStatus, len(pulls))
+	errs := make([]error, len(pulls))
+
+	// Each pull request is three requests, so only a few at once to stay clear of GitHub's secondary rate limits
+	limit := make(chan struct{}, pullStatusLimit)
+
+	var wait sync.WaitGroup
+	for index, pull := range pulls {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+
+			statuses[index], errs[index] = pullStatus(repoUrl, token, pull)
+		}()
+	}
+	wait.Wait()
+
+	return statuses, errors.Join(errs...)
+}
+
+func pullStatus(repoUrl, token string, pull Github_Pull) (PullStatus, error) {
+	status := PullStatus{Github_Pull: pull}
+
+	reviews, err := requestGithub[[]Github_Review]("GET", fmt.Sprintf("%s/pulls/%d/reviews?per_page=100", repoUrl, pull.Number), token, nil)
+	if err != nil {
+		return status, err
+	}
+	status.Review = reviewState(reviews, len(pull.Requested_reviewers))
+
+	combined, err := requestGithub[Github_Combined_Status]("GET", fmt.Sprintf("%s/commits/%s/status", repoUrl, pull.Head.Sha), token, nil)
+	if err != nil {
+		return status, err
+	}
+
+	runs, err := requestGithub[Github_Check_Runs]("GET", fmt.Sprintf("%s/commits/%s/check-runs?per_page=100", repoUrl, pull.Head.Sha), token, nil)
+	if err != nil {
+		return status, err
+	}
+	status.Checks = combineChecks(combined, runs)
+
+	return status, nil
+}
+
+// reviewState uses the latest approval or change request from each reviewer, as GitHub does.
+// Reviews come oldest first, and comments don't change the state
+func reviewState(reviews []Github_Review, requested int) string {
+	latest := map[string]string{}
+	for _, review := range reviews {
+		switch review.State {
+		case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+			latest[review.User.Login] = review.State
+		}
+	}
+
+	var approved bool
+	for _, state := range latest {
+		if state == "CHANGES_REQUESTED" {
+			return ReviewChangesRequested
+		}
+		approved = approved || state == "APPROVED"
+	}
+
+	switch {
+	case requested > 0:
+		return ReviewRequired
+	case approved:
+		return ReviewApproved
+	}
+	return ""
+}
+
+// combineChecks puts the commit statuses and the check runs, such as GitHub Actions, together
+func combineChecks(combined Github_Combined_Status, runs Github_Check_Runs) Checks {
+	var checks Checks
+
+	for _, status := range combined.Statuses {
+		state := CheckPending
+		switch status.State {
+		case "success":
+			state = CheckPassing
+		case "failure", "error":
+			state = CheckFailing
+		}
+		checks.Checks = append(checks.Checks, Check{Name: status.Context, State: state, URL: status.Target_url})
+	}
+
+	for _, run := range runs.Check_runs {
+		state := CheckPending
+		if run.Status == "completed" {
+			switch run.Conclusion {
+			case "success":
+				state = CheckPassing
+			case "neutral", "skipped":
+				state = CheckSkipped
+			default:
+				state = CheckFailing
+			}
+		}
+		checks.Checks = append(checks.Checks, Check{Name: run.Name, State: state, URL: run.Html_url})
+	}
+
+	for _, check := range checks.Checks {
+		switch check.State {
+		case CheckPassing:
+			checks.Passing++
+		case CheckFailing:
+			checks.Failing++
+		case CheckPending:
+			checks.Pending++
+		}
+	}
+
+	switch {
+	case checks.Failing > 0:
+		checks.State = CheckFailing
+	case checks.Pending > 0:
+		checks.State = CheckPending
+	case checks.Passing > 0:
+		checks.State = CheckPassing
+	}
+
+	return checks
+}
+
+// CheckoutPullRequest fetches the head of the pull request from refs/pull/N/head, which works for forks too, and checks it out.
+// It returns the name of the local branch
+func CheckoutPullRequest(remote string, number int) (string, error) {
+	GitCredentials, err := getGitCredentials()
+	if err != nil {
+		return "", err
+	}
+
+	pull, err := GetPullRequest(number)
+	if err != nil {
+		return "", err
+	}
+
+	branch := checkoutBranch(pull.Github_Pull, GitCredentials.Owner+"/"+GitCredentials.Repo)
+	ref := fmt.Sprintf("refs/pull/%d/head", number)
+
+	// Fetching into the checked out branch isn't allowed, so it's fast forwarded instead
+	if current, _ := CurrentBranch(); current == branch {
+		if _, err := runGit("pull", "--ff-only", remote, ref); err != nil {
+			return branch, err
+		}
+		return branch, nil
+	}
+
+	// Without a + the fetch fails rather than throwing away local commits on the branch
+	if _, err := runGit("fetch", remote, ref+":refs/heads/"+branch); err != nil {
+		return branch, err
+	}
+	if _, err := runGit("checkout", branch); err != nil {
+		return branch, err
+	}
+	return branch, nil
+}
+
+// checkoutBranch names the local branch after the head branch, prefixed by the owner for a fork so it can't clash with a local branch
+func checkoutBranch(pull Github_Pull, repository string) string {
+	switch {
+	case pull.Head.Ref == "":
+		return fmt.Sprintf("pr-%d", pull.Number)
+	case pull.Head.Repo.Full_name == repository:
+		return pull.Head.Ref
+	case pull.Head.Repo.Full_name == "":
+		// The fork was deleted
+		return fmt.Sprintf("pr-%d/%s", pull.Number, pull.Head.Ref)
+	}
+
+	owner, _, _ := strings.Cut(pull.Head.Repo.Full_name, "/")
+	return owner + "/" + pull.Head.Ref
+}
@@ -0,0 +1,216 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCreatePull(t *testing.T) {
+	t.Log("Testing createPull asks for reviews and adds labels after creating the pull request")
+
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+strings.TrimSpace(string(body)))
+
+		w.WriteHeader(http.StatusCreated)
+		switch r.URL.Path {
+		case "/pulls":
+			w.Write([]byte(`{"number": 5, "html_url": "https://github.com/o/r/pull/5"}`))
+		case "/issues/5/labels":
+			w.Write([]byte(`[]`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	pull, err := createPull(server.URL, "token", Github_Pull_Request{Title: "Login page", Head: "feature/login", Base: "main", Draft: true}, []string{"alice"}, []string{"enhancement"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pull.Html_url != "https://github.com/o/r/pull/5" {
+		t.Errorf("got %q", pull.Html_url)
+	}
+
+	want := []string{
+		`POST /pulls {"title":"Login page","head":"feature/login","base":"main","body":"","draft":true}`,
+		`POST /pulls/5/requested_reviewers {"reviewers":["alice"]}`,
+		`POST /issues/5/labels {"labels":["enhancement"]}`,
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("expected %v, got %v", want, requests)
+	}
+	for index := range want {
+		if requests[index] != want[index] {
+			t.Errorf("request %d: got %q, want %q", index, requests[index], want[index])
+		}
+	}
+}
+
+func TestReviewState(t *testing.T) {
+	t.Log("Testing reviewState uses the latest review of each reviewer")
+
+	review := func(login, state string) Github_Review {
+		return Github_Review{User: Github_Assignee{Login: login}, State: state}
+	}
+
+	cases := []struct {
+		reviews   []Github_Review
+		requested int
+		want      string
+	}{
+		{nil, 0, ""},
+		{nil, 1, ReviewRequired},
+		{[]Github_Review{review("alice", "APPROVED")}, 0, ReviewApproved},
+		{[]Github_Review{review("alice", "CHANGES_REQUESTED"), review("alice", "COMMENTED")}, 0, ReviewChangesRequested},
+		{[]Github_Review{review("alice", "CHANGES_REQUESTED"), review("alice", "APPROVED")}, 0, ReviewApproved},
+		{[]Github_Review{review("alice", "APPROVED"), review("bob", "CHANGES_REQUESTED")}, 0, ReviewChangesRequested},
+		{[]Github_Review{review("alice", "APPROVED"), review("alice", "DISMISSED")}, 0, ""},
+	}
+
+	for index, c := range cases {
+		if got := reviewState(c.reviews, c.requested); got != c.want {
+			t.Errorf("case %d: got %q, want %q", index, got, c.want)
+		}
+	}
+}
+
+func TestCombineChecks(t *testing.T) {
+	t.Log("Testing combineChecks puts commit statuses and check runs together")
+
+	combined := Github_Combined_Status{Statuses: []Github_Commit_Status{{Context: "ci/jenkins", State: "success"}}}
+	runs := Github_Check_Runs{Check_runs: []Github_Check_Run{
+		{Name: "test", Status: "completed", Conclusion: "success"},
+		{Name: "lint", Status: "completed", Conclusion: "skipped"},
+		{Name: "build", Status: "in_progress"},
+	}}
+
+	checks := combineChecks(combined, runs)
+	if checks.State != CheckPending || checks.Passing != 2 || checks.Pending != 1 || len(checks.Checks) != 4 {
+		t.Errorf("got %+v", checks)
+	}
+
+	runs.Check_runs[2] = Github_Check_Run{Name: "build", Status: "completed", Conclusion: "timed_out"}
+	if checks := combineChecks(combined, runs); checks.State != CheckFailing || checks.Failing != 1 {
+		t.Errorf("a timed out run should fail, got %+v", checks)
+	}
+
+	if checks := combineChecks(Github_Combined_Status{State: "pending"}, Github_Check_Runs{}); checks.State != "" {
+		t.Errorf("no checks should have no state, got %+v", checks)
+	}
+}
+
+func TestCheckoutBranch(t *testing.T) {
+	t.Log("Testing checkoutBranch names forks after their owner")
+
+	pull := Github_Pull{Number: 8}
+	pull.Head.Ref = "main"
+
+	pull.Head.Repo.Full_name = "o/r"
+	if got := checkoutBranch(pull, "o/r"); got != "main" {
+		t.Errorf("got %q, want main", got)
+	}
+
+	pull.Head.Repo.Full_name = "alice/r"
+	if got := checkoutBranch(pull, "o/r"); got != "alice/main" {
+		t.Errorf("got %q, want alice/main", got)
+	}
+
+	pull.Head.Repo.Full_name = ""
+	if got := checkoutBranch(pull, "o/r"); got != "pr-8/main" {
+		t.Errorf("got %q, want pr-8/main", got)
+	}
+}
+
+func TestListPulls(t *testing.T) {
+	t.Log("Testing listPulls reads every page")
+
+	const total = 250
+
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		pages = append(pages, r.URL.Query().Get("page"))
+
+		var pulls []Github_Pull
+		for number := (page-1)*pullsPerPage + 1; number <= min(page*pullsPerPage, total); number++ {
+			pulls = append(pulls, Github_Pull{Number: number})
+		}
+		json.NewEncoder(w).Encode(pulls)
+	}))
+	defer server.Close()
+
+	pulls, err := listPulls(server.URL, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pulls) != total || pulls[total-1].Number != total {
+		t.Errorf("got %d pull requests, want %d", len(pulls), total)
+	}
+	if strings.Join(pages, ",") != "1,2,3" {
+		t.Errorf("asked for pages %v, want 1,2,3", pages)
+	}
+}
+
+func TestPullStatusesLimit(t *testing.T) {
+	t.Log("Testing pullStatuses keeps the order and only fetches a few pull requests at once")
+
+	var lock sync.Mutex
+	var running, most int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		running++
+		most = max(most, running)
+		lock.Unlock()
+
+		// Long enough for the requests to overlap
+		time.Sleep(5 * time.Millisecond)
+
+		lock.Lock()
+		running--
+		lock.Unlock()
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/reviews"):
+			w.Write([]byte(`[]`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	var pulls []Github_Pull
+	for number := 1; number <= pullStatusLimit*3; number++ {
+		pull := Github_Pull{Number: number}
+		pull.Head.Sha = fmt.Sprintf("sha%d", number)
+		pulls = append(pulls, pull)
+	}
+
+	statuses, err := pullStatuses(server.URL, "token", pulls)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for index, status := range statuses {
+		if status.Number != index+1 {
+			t.Errorf("status %d is for #%d", index, status.Number)
+		}
+	}
+
+	// Each pull request makes its requests one after another, so at most one is running for each of them
+	if most > pullStatusLimit {
+		t.Errorf("%d requests ran at once, the limit is %d", most, pullStatusLimit)
+	}
+}
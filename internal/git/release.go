@@ -0,0 +1,173 @@
+package git
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+)
+
+// ErrTagNotOnRemote is returned when the tag for a release hasn't been pushed, GitHub would make a new tag from the default branch
+var ErrTagNotOnRemote = errors.New("the tag is not on the remote")
+
+// ChecksumsFileName is the asset holding the SHA-256 of every other asset
+const ChecksumsFileName string = "SHA256SUMS"
+
+type Github_Release_Request struct {
+	Tag_name   string `json:"tag_name"`
+	Name       string `json:"name"`
+	Body       string `json:"body"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+}
+
+type Github_Release_Asset struct {
+	Id     int    `json:"id"`
+	Name   string `json:"name"`
+	Size   int    `json:"size"`
+	Digest string `json:"digest"`
+}
+
+type Github_Release struct {
+	Id         int                    `json:"id"`
+	Tag_name   string                 `json:"tag_name"`
+	Name       string                 `json:"name"`
+	Body       string                 `json:"body"`
+	Draft      bool                   `json:"draft"`
+	Prerelease bool                   `json:"prerelease"`
+	Html_url   string                 `json:"html_url"`
+	Upload_url string                 `json:"upload_url"`
+	Assets     []Github_Release_Asset `json:"assets"`
+}
+
+// tagOnRemote reports whether the remote has the tag
+func tagOnRemote(remote, tag string) (bool, error) {
+	remoteTags, err := runGit("ls-remote", "--tags", remote, "refs/tags/"+tag)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.TrimSpace(remoteTags) != "", nil
+}
+
+// PublishRelease creates the GitHub Release for the tag, or updates it if it's already there, and uploads the assets.
+// Tags with a SemVer pre-release are marked as pre-releases. Assets are only uploaded again when their contents changed,
+// and a SHA256SUMS asset lists the checksum of each one, so running it twice leaves the release as it was.
+// The tag has to be pushed to the remote first, otherwise ErrTagNotOnRemote is returned.
+func PublishRelease(tag, name, notes, remote string, assets []string) (Github_Release, error) {
+	var release Github_Release
+
+	GitCredentials, err := getGitCredentials()
+	if err != nil {
+		return release, err
+	}
+
+	_, version, ErrParsing := ParseModuleTag(tag)
+	if ErrParsing != nil {
+		return release, ErrParsing
+	}
+
+	// Without the tag on the remote GitHub would make a new one from the default branch
+	pushed, ErrCheckingRemote := tagOnRemote(remote, tag)
+	if ErrCheckingRemote != nil {
+		return release, ErrCheckingRemote
+	}
+	if !pushed {
+		return release, fmt.Errorf("%w, push it first with: git push %s refs/tags/%s", ErrTagNotOnRemote, remote, tag)
+	}
+
+	if name == "" {
+		name = tag
+	}
+
+	releaseRequest := Github_Release_Request{
+		Tag_name:   tag,
+		Name:       name,
+		Body:       notes,
+		Prerelease: version.IsPrerelease(),
+	}
+
+	repoUrl := fmt.Sprintf("https://api.github.com/repos/%s/%s", GitCredentials.Owner, GitCredentials.Repo)
+
+	existing, ErrGettingRelease := requestGithub[Github_Release]("GET", repoUrl+"/releases/tags/"+url.PathEscape(tag), GitCredentials.Token, nil)
+	switch {
+	case errors.Is(ErrGettingRelease, ErrGithubNotFound):
+		release, err = requestGithub[Github_Release]("POST", repoUrl+"/releases", GitCredentials.Token, releaseRequest)
+		if err != nil {
+			return release, err
+		}
+		aphrodite.PrintInfo(fmt.Sprintf("Created the release for %s\n", tag))
+	case ErrGettingRelease != nil:
+		return release, ErrGettingRelease
+	default:
+		release, err = requestGithub[Github_Release]("PATCH", fmt.Sprintf("%s/releases/%d", repoUrl, existing.Id), GitCredentials.Token, releaseRequest)
+		if err != nil {
+			return release, err
+		}
+		aphrodite.PrintInfo(fmt.Sprintf("Updated the release for %s\n", tag))
+	}
+
+	if len(assets) == 0 {
+		return release, nil
+	}
+
+	var checksums strings.Builder
+	for _, asset := range assets {
+		contents, ErrReadingAsset := os.ReadFile(asset)
+		if ErrReadingAsset != nil {
+			return release, ErrReadingAsset
+		}
+
+		sum := sha256.Sum256(contents)
+		checksums.WriteString(fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), filepath.Base(asset)))
+
+		if err := uploadAsset(release, repoUrl, GitCredentials.Token, filepath.Base(asset), contents); err != nil {
+			return release, err
+		}
+	}
+
+	if err := uploadAsset(release, repoUrl, GitCredentials.Token, ChecksumsFileName, []byte(checksums.String())); err != nil {
+		return release, err
+	}
+
+	return release, nil
+}
+
+// uploadAsset uploads the contents, replacing an asset of the same name unless it already has the same SHA-256
+func uploadAsset(release Github_Release, repoUrl, token, name string, contents []byte) error {
+	sum := sha256.Sum256(contents)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	for _, existing := range release.Assets {
+		if existing.Name != name {
+			continue
+		}
+
+		if existing.Digest == digest {
+			fmt.Printf("%s is already uploaded\n", name)
+			return nil
+		}
+
+		if _, err := requestGithub[struct{}]("DELETE", fmt.Sprintf("%s/releases/assets/%d", repoUrl, existing.Id), token, nil); err != nil {
+			return fmt.Errorf("unable to replace %s: %w", name, err)
+		}
+	}
+
+	// The upload url is a template, https://uploads.github.com/repos/o/r/releases/1/assets{?name,label}
+	uploadUrl, _, _ := strings.Cut(release.Upload_url, "{")
+	uploadUrl += "?name=" + url.QueryEscape(name)
+
+	if _, err := requestGithub[Github_Release_Asset]("POST", uploadUrl, token, bytes.NewReader(contents)); err != nil {
+		return fmt.Errorf("unable to upload %s: %w", name, err)
+	}
+
+	fmt.Printf("Uploaded %s (%s)\n", name, digest)
+	return nil
+}
@@ -0,0 +1,114 @@
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestUploadAsset(t *testing.T) {
+	t.Log("Testing uploadAsset only replaces assets which changed")
+
+	var requests []string
+	var uploaded string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		if r.Method == "POST" {
+			body, _ := io.ReadAll(r.Body)
+			uploaded = string(body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 3, "name": "app.tar.gz"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	contents := []byte("release contents")
+	sum := sha256.Sum256(contents)
+
+	release := Github_Release{
+		Upload_url: server.URL + "/uploads/assets{?name,label}",
+		Assets: []Github_Release_Asset{
+			{Id: 1, Name: "app.tar.gz", Digest: "sha256:" + hex.EncodeToString(sum[:])},
+		},
+	}
+
+	if err := uploadAsset(release, server.URL, "token", "app.tar.gz", contents); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 0 {
+		t.Fatalf("expected an unchanged asset to be skipped, got %v", requests)
+	}
+
+	if err := uploadAsset(release, server.URL, "token", "app.tar.gz", []byte("new contents")); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"DELETE /releases/assets/1", "POST /uploads/assets?name=app.tar.gz"}
+	if len(requests) != len(want) {
+		t.Fatalf("expected %v, got %v", want, requests)
+	}
+	for index := range want {
+		if requests[index] != want[index] {
+			t.Errorf("request %d: got %q, want %q", index, requests[index], want[index])
+		}
+	}
+
+	if uploaded != "new contents" {
+		t.Errorf("uploaded %q", uploaded)
+	}
+}
+
+func TestTagOnRemote(t *testing.T) {
+	t.Log("Testing tagOnRemote only finds pushed tags")
+
+	directory := t.TempDir()
+	remote := filepath.Join(directory, "remote.git")
+	t.Chdir(directory)
+
+	for _, arguments := range [][]string{
+		{"init", "-q", "--bare", remote},
+		{"init", "-q", "local"},
+	} {
+		if _, err := runGit(arguments...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Chdir(filepath.Join(directory, "local"))
+	for _, arguments := range [][]string{
+		{"config", "user.name", "Tester"},
+		{"config", "user.email", "tester@example.com"},
+		{"remote", "add", "origin", remote},
+		{"commit", "-q", "--allow-empty", "-m", "first"},
+		{"tag", "v1.0.0"},
+		{"tag", "v1.1.0"},
+		{"push", "-q", "origin", "refs/tags/v1.0.0"},
+	} {
+		if _, err := runGit(arguments...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := map[string]bool{
+		"v1.0.0": true,
+		"v1.1.0": false, // Only made locally
+		"v1.0":   false,
+	}
+
+	for tag, want := range cases {
+		got, err := tagOnRemote("origin", tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("tagOnRemote(%q) = %v, want %v", tag, got, want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package git
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+)
+
+// SignatureStatus is what verifying a tag found
+type SignatureStatus string
+
+const (
+	SignatureGood        SignatureStatus = "good"
+	SignatureBad         SignatureStatus = "bad"         // Signed, but the signature doesn't check out or the key isn't trusted
+	SignatureUnsigned    SignatureStatus = "unsigned"    // An annotated tag without a signature
+	SignatureLightweight SignatureStatus = "lightweight" // Not a tag object at all, so there is nothing to sign
+)
+
+// TagSignature is the result of verifying one tag
+type TagSignature struct {
+	Tag    string
+	Status SignatureStatus
+	Format string // openpgp, ssh or x509, empty when unsigned
+	Detail string // Who signed it when good, or why it failed
+}
+
+// VerifyTag checks the signature of the tag with git verify-tag, which uses gpg.format and the allowed signers from the user's git config
+func VerifyTag(tag string) (TagSignature, error) {
+	signature := TagSignature{Tag: tag}
+
+	objectType, err := runGit("cat-file", "-t", "refs/tags/"+tag)
+	if err != nil {
+		return signature, err
+	}
+
+	if strings.TrimSpace(objectType) != "tag" {
+		signature.Status = SignatureLightweight
+		return signature, nil
+	}
+
+	tagObject, err := runGit("cat-file", "tag", "refs/tags/"+tag)
+	if err != nil {
+		return signature, err
+	}
+
+	signature.Format = signatureFormat(tagObject)
+	if signature.Format == "" {
+		signature.Status = SignatureUnsigned
+		return signature, nil
+	}
+
+	cmd := exec.Command("git", "verify-tag", "refs/tags/"+tag)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	// git verify-tag writes what gpg or ssh-keygen said to stderr, and fails for a bad or untrusted signature
+	ErrVerifying := cmd.Run()
+	if ErrVerifying != nil {
+		if _, exited := ErrVerifying.(*exec.ExitError); !exited {
+			return signature, ErrVerifying
+		}
+		signature.Status = SignatureBad
+	} else {
+		signature.Status = SignatureGood
+	}
+
+	signature.Detail = signatureDetail(stderr.String(), signature.Status)
+	return signature, nil
+}
+
+// signatureFormat finds the kind of signature on the end of a tag object
+func signatureFormat(tagObject string) string {
+	switch {
+	case strings.Contains(tagObject, "-----BEGIN PGP SIGNATURE-----"):
+		return "openpgp"
+	case strings.Contains(tagObject, "-----BEGIN SSH SIGNATURE-----"):
+		return "ssh"
+	case strings.Contains(tagObject, "-----BEGIN SIGNED MESSAGE-----"):
+		return "x509"
+	}
+	return ""
+}
+
+// signatureDetail picks the most useful line of what gpg or ssh-keygen printed
+func signatureDetail(output string, status SignatureStatus) string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "gpg:"))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if status == SignatureGood {
+		for _, line := range lines {
+			if strings.HasPrefix(line, "Good") {
+				return line
+			}
+		}
+	}
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, "BAD") || strings.Contains(line, "Can't check signature") || strings.Contains(line, "No public key") || strings.Contains(line, "needs to be configured") || strings.Contains(line, "Could not verify") {
+			return line
+		}
+	}
+
+	if len(lines) > 0 {
+		return lines[len(lines)-1]
+	}
+	return ""
+}
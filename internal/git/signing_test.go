@@ -0,0 +1,78 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyTag(t *testing.T) {
+	t.Log("Testing VerifyTag with SSH signing")
+
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is needed to sign tags")
+	}
+
+	directory := t.TempDir()
+	t.Chdir(directory)
+
+	key := filepath.Join(directory, "key")
+	if out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", key, "-C", "tester@example.com").CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen failed: %s", out)
+	}
+
+	publicKey, err := os.ReadFile(key + ".pub")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	allowedSigners := filepath.Join(directory, "allowed_signers")
+	if err := os.WriteFile(allowedSigners, append([]byte("tester@example.com "), publicKey...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, arguments := range [][]string{
+		{"init", "-q"},
+		{"config", "user.name", "Tester"},
+		{"config", "user.email", "tester@example.com"},
+		{"config", "gpg.format", "ssh"},
+		{"config", "user.signingkey", key + ".pub"},
+		{"commit", "-q", "--allow-empty", "-m", "first"},
+		{"tag", "-m", "unsigned", "v1.0.0"},
+		{"tag", "--sign", "-m", "signed", "v1.1.0"},
+		{"tag", "v1.2.0"},
+	} {
+		if _, err := runGit(arguments...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := map[string]SignatureStatus{
+		"v1.0.0": SignatureUnsigned,
+		"v1.1.0": SignatureBad, // No allowed signers are configured yet
+		"v1.2.0": SignatureLightweight,
+	}
+
+	for tag, want := range cases {
+		signature, err := VerifyTag(tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if signature.Status != want {
+			t.Errorf("VerifyTag(%q) = %s, want %s (%s)", tag, signature.Status, want, signature.Detail)
+		}
+	}
+
+	if _, err := runGit("config", "gpg.ssh.allowedSignersFile", allowedSigners); err != nil {
+		t.Fatal(err)
+	}
+
+	signature, err := VerifyTag("v1.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signature.Status != SignatureGood || signature.Format != "ssh" {
+		t.Errorf("expected a good ssh signature, got %+v", signature)
+	}
+}
@@ -0,0 +1,130 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotRepository is returned by CheckRepo for a folder without its own .git
+var ErrNotRepository = errors.New("not a git folder")
+
+// RepoStatus is where a repository's branch stands against its upstream and what's waiting in its working tree
+type RepoStatus struct {
+	Name       string    `json:"name"`
+	Branch     string    `json:"branch"`             // (detached) when HEAD isn't on a branch
+	Upstream   string    `json:"upstream,omitempty"` // Empty when the branch doesn't track one, and then ahead and behind are 0
+	Ahead      int       `json:"ahead"`
+	Behind     int       `json:"behind"`
+	Staged     int       `json:"staged"`
+	Modified   int       `json:"modified"`
+	Untracked  int       `json:"untracked"`
+	Conflicted int       `json:"conflicted"`
+	Stashes    int       `json:"stashes"`
+	LastCommit time.Time `json:"last_commit"` // Zero when there aren't any commits yet
+	Error      string    `json:"error,omitempty"`
+}
+
+// CheckRepo reads the status of the repository in the directory.
+// Only folders with their own .git count, so a folder inside another repository isn't mistaken for one
+func CheckRepo(directory string) (RepoStatus, error) {
+	status := RepoStatus{Name: filepath.Base(directory)}
+
+	if _, err := os.Stat(filepath.Join(directory, ".git")); err != nil {
+		return status, ErrNotRepository
+	}
+
+	out, err := runGitIn(directory, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return status, err
+	}
+	parseStatus(out, &status)
+
+	// Both fail when there's nothing there, which is none rather than an error
+	if stashes, err := runGitIn(directory, "rev-list", "--walk-reflogs", "--count", "refs/stash"); err == nil {
+		status.Stashes, _ = strconv.Atoi(strings.TrimSpace(stashes))
+	}
+	if date, err := runGitIn(directory, "log", "-1", "--format=%cI"); err == nil {
+		status.LastCommit, _ = time.Parse(time.RFC3339, strings.TrimSpace(date))
+	}
+
+	return status, nil
+}
+
+// CheckRepos checks each directory at the same time, leaving out the ones which aren't repositories.
+// The statuses keep the order of the directories, and a repository git fails on has the error set rather than stopping the rest
+func CheckRepos(directories []string) []RepoStatus {
+	statuses := make([]RepoStatus, len(directories))
+	found := make([]bool, len(directories))
+
+	// Enough to keep the disk busy without starting hundreds of git processes at once
+	limit := make(chan struct{}, runtime.NumCPU()*2)
+
+	var wait sync.WaitGroup
+	for index, directory := range directories {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+
+			status, err := CheckRepo(directory)
+			if errors.Is(err, ErrNotRepository) {
+				return
+			}
+			if err != nil {
+				status.Error = err.Error()
+			}
+			statuses[index], found[index] = status, true
+		}()
+	}
+	wait.Wait()
+
+	var repos []RepoStatus
+	for index, status := range statuses {
+		if found[index] {
+			repos = append(repos, status)
+		}
+	}
+	return repos
+}
+
+// parseStatus reads git status --porcelain=v2 --branch
+func parseStatus(out string, status *RepoStatus) {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "#":
+			switch {
+			case fields[1] == "branch.head" && len(fields) > 2:
+				status.Branch = fields[2]
+			case fields[1] == "branch.upstream" && len(fields) > 2:
+				status.Upstream = fields[2]
+			case fields[1] == "branch.ab" && len(fields) > 3:
+				status.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[2], "+"))
+				status.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[3], "-"))
+			}
+		case "1", "2":
+			// XY is the change in the index then the change in the working tree, . for none
+			if fields[1][0] != '.' {
+				status.Staged++
+			}
+			if len(fields[1]) > 1 && fields[1][1] != '.' {
+				status.Modified++
+			}
+		case "u":
+			status.Conflicted++
+		case "?":
+			status.Untracked++
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseStatus(t *testing.T) {
+	t.Log("Testing parseStatus counts each kind of change")
+
+	out := `# branch.oid 3e852ae
+# branch.head main
+# branch.upstream origin/main
+# branch.ab +2 -5
+1 M. N... 100644 100644 100644 aaa bbb staged.go
+1 .M N... 100644 100644 100644 aaa bbb modified.go
+1 MM N... 100644 100644 100644 aaa bbb both.go
+2 R. N... 100644 100644 100644 aaa bbb R100 new.go	old.go
+u UU N... 100644 100644 100644 100644 aaa bbb ccc conflict.go
+? untracked.go
+`
+
+	var status RepoStatus
+	parseStatus(out, &status)
+
+	want := RepoStatus{Branch: "main", Upstream: "origin/main", Ahead: 2, Behind: 5, Staged: 3, Modified: 2, Untracked: 1, Conflicted: 1}
+	if status != want {
+		t.Errorf("got %+v, want %+v", status, want)
+	}
+}
+
+func TestCheckRepos(t *testing.T) {
+	t.Log("Testing CheckRepos skips folders which aren't repositories")
+
+	root := t.TempDir()
+	for _, name := range []string{"notes", "app"} {
+		os.Mkdir(filepath.Join(root, name), 0o755)
+	}
+
+	if out, err := exec.Command("git", "init", "-q", "-b", "main", filepath.Join(root, "app")).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %s", out)
+	}
+	os.WriteFile(filepath.Join(root, "app", "main.go"), []byte("package main\n"), 0o644)
+
+	repos := CheckRepos([]string{filepath.Join(root, "notes"), filepath.Join(root, "app")})
+	if len(repos) != 1 {
+		t.Fatalf("expected only app, got %+v", repos)
+	}
+
+	app := repos[0]
+	if app.Name != "app" || app.Branch != "main" || app.Untracked != 1 || !app.LastCommit.IsZero() || app.Error != "" {
+		t.Errorf("got %+v", app)
+	}
+}
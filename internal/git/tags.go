@@ -0,0 +1,120 @@
+package git
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jonathon-chew/go-repoflow/internal/semver"
+)
+
+// TagInfo is a version tag with where it points and how it got there
+type TagInfo struct {
+	Name       string         `json:"name"`
+	Module     string         `json:"module,omitempty"` // The tag prefix, such as services/api/, empty for the root module
+	Version    semver.Version `json:"-"`
+	Prerelease bool           `json:"prerelease"`
+	Date       time.Time      `json:"date"`
+	Tagger     string         `json:"tagger,omitempty"` // Empty for lightweight tags
+	Commit     string         `json:"commit"`
+	Previous   string         `json:"previous,omitempty"` // The tag of the same module just before this one
+	Commits    int            `json:"commits_since_previous"`
+	Pushed     *bool          `json:"pushed"` // Nil when the remote couldn't be reached
+}
+
+// tagFormat gives the name, date, tagger, the object the tag points at and, for annotated tags, the commit under it
+const tagFormat string = "--format=%(refname:short)%1f%(creatordate:iso-strict)%1f%(taggername)%1f%(objectname)%1f%(*objectname)"
+
+// ListTags returns the semantic version tags of every module, highest version first within each module, and the names of the tags which aren't versions.
+// Pushed is worked out from the tags on the remote, and left nil if it can't be reached
+func ListTags(remote string) ([]TagInfo, []string, error) {
+	out, err := runGit("for-each-ref", tagFormat, "refs/tags")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tags, others := parseTags(out)
+
+	// Count the commits from the previous version of the same module, lowest version first
+	for index := len(tags) - 1; index >= 0; index-- {
+		revisionRange := tags[index].Commit
+		if index+1 < len(tags) && tags[index+1].Module == tags[index].Module {
+			tags[index].Previous = tags[index+1].Name
+			revisionRange = tags[index+1].Commit + ".." + tags[index].Commit
+		}
+
+		count, err := runGit("rev-list", "--count", revisionRange)
+		if err != nil {
+			return nil, nil, err
+		}
+		tags[index].Commits, _ = strconv.Atoi(strings.TrimSpace(count))
+	}
+
+	if remote == "" {
+		remote = "origin"
+	}
+
+	if remoteTags, err := runGit("ls-remote", "--tags", remote); err == nil {
+		pushed := map[string]bool{}
+		for _, line := range strings.Split(remoteTags, "\n") {
+			if _, ref, found := strings.Cut(line, "\t"); found {
+				pushed[strings.TrimSuffix(strings.TrimPrefix(ref, "refs/tags/"), "^{}")] = true
+			}
+		}
+
+		for index := range tags {
+			isPushed := pushed[tags[index].Name]
+			tags[index].Pushed = &isPushed
+		}
+	}
+
+	return tags, others, nil
+}
+
+// parseTags reads the for-each-ref output, sorting the version tags and keeping the names of the rest
+func parseTags(out string) ([]TagInfo, []string) {
+	var tags []TagInfo
+	var others []string
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 5 || fields[0] == "" {
+			continue
+		}
+
+		prefix, version, err := ParseModuleTag(fields[0])
+		if err != nil {
+			others = append(others, fields[0])
+			continue
+		}
+
+		tag := TagInfo{
+			Name:       fields[0],
+			Module:     prefix,
+			Version:    version,
+			Prerelease: version.IsPrerelease(),
+			Tagger:     fields[2],
+			Commit:     fields[3],
+		}
+
+		// Annotated tags point at a tag object, with the commit under it
+		if fields[4] != "" {
+			tag.Commit = fields[4]
+		}
+
+		tag.Date, _ = time.Parse(time.RFC3339, fields[1])
+
+		tags = append(tags, tag)
+	}
+
+	slices.SortStableFunc(tags, func(a, b TagInfo) int {
+		if a.Module != b.Module {
+			return cmp.Compare(a.Module, b.Module)
+		}
+		return semver.Compare(b.Version, a.Version)
+	})
+
+	return tags, others
+}
@@ -0,0 +1,44 @@
+package git
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	t.Log("Testing parseTags")
+
+	out := "v1.0.0\x1f2026-01-02T10:00:00+00:00\x1fAlice\x1faaaa\x1fcccc\n" +
+		"v1.10.0\x1f2026-03-02T10:00:00+00:00\x1f\x1fbbbb\x1f\n" +
+		"v1.2.0-rc.1\x1f2026-02-02T10:00:00+00:00\x1fAlice\x1fdddd\x1feeee\n" +
+		"api/v0.1.0\x1f2026-02-03T10:00:00+00:00\x1fBob\x1fffff\x1f1111\n" +
+		"nightly\x1f2026-02-04T10:00:00+00:00\x1f\x1f2222\x1f\n"
+
+	tags, others := parseTags(out)
+
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+
+	want := []string{"v1.10.0", "v1.2.0-rc.1", "v1.0.0", "api/v0.1.0"}
+	if !slices.Equal(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+
+	if !slices.Equal(others, []string{"nightly"}) {
+		t.Errorf("expected nightly to be kept separately, got %v", others)
+	}
+
+	if tags[2].Commit != "cccc" || tags[0].Commit != "bbbb" {
+		t.Errorf("annotated tags should give the commit under them, lightweight ones the commit itself: %+v", tags)
+	}
+
+	if !tags[1].Prerelease || tags[0].Prerelease {
+		t.Error("only v1.2.0-rc.1 is a pre-release")
+	}
+
+	if tags[3].Module != "api/" || tags[3].Date.Day() != 3 {
+		t.Errorf("api/v0.1.0 was read as %+v", tags[3])
+	}
+}
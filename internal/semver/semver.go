@@ -0,0 +1,258 @@
+package semver
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Version is a Semantic Versioning 2.0.0 version, https://semver.org
+type Version struct {
+	Prefix     string // "v" when the tag was written v1.2.3, it isn't part of the version
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease []string // The dot separated identifiers after -, e.g. rc.1
+	Build      []string // The dot separated identifiers after +, ignored when comparing
+}
+
+// Parse reads a version such as 1.2.3, v1.2.3-rc.1 or v1.2.3+build5
+func Parse(text string) (Version, error) {
+	var version Version
+
+	rest := text
+	if strings.HasPrefix(rest, "v") {
+		version.Prefix = "v"
+		rest = rest[1:]
+	}
+
+	rest, build, hasBuild := strings.Cut(rest, "+")
+	if hasBuild {
+		identifiers, err := parseIdentifiers(build, false)
+		if err != nil {
+			return version, fmt.Errorf("%s has invalid build metadata: %w", text, err)
+		}
+		version.Build = identifiers
+	}
+
+	core, prerelease, hasPrerelease := strings.Cut(rest, "-")
+	if hasPrerelease {
+		identifiers, err := parseIdentifiers(prerelease, true)
+		if err != nil {
+			return version, fmt.Errorf("%s has an invalid pre-release: %w", text, err)
+		}
+		version.Prerelease = identifiers
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return version, fmt.Errorf("%s is not in the form major.minor.patch", text)
+	}
+
+	numbers := make([]int, 3)
+	for index, part := range parts {
+		number, err := parseNumber(part)
+		if err != nil {
+			return version, fmt.Errorf("%s: %w", text, err)
+		}
+		numbers[index] = number
+	}
+	version.Major, version.Minor, version.Patch = numbers[0], numbers[1], numbers[2]
+
+	return version, nil
+}
+
+// parseNumber reads a numeric identifier, which can't have leading zeros
+func parseNumber(part string) (int, error) {
+	if part == "" {
+		return 0, fmt.Errorf("empty version number")
+	}
+
+	for _, character := range part {
+		if character < '0' || character > '9' {
+			return 0, fmt.Errorf("%q is not a number", part)
+		}
+	}
+
+	if len(part) > 1 && part[0] == '0' {
+		return 0, fmt.Errorf("%q has a leading zero", part)
+	}
+
+	return strconv.Atoi(part)
+}
+
+// parseIdentifiers splits the dot separated identifiers, numeric pre-release identifiers can't have leading zeros
+func parseIdentifiers(text string, prerelease bool) ([]string, error) {
+	identifiers := strings.Split(text, ".")
+
+	for _, identifier := range identifiers {
+		if identifier == "" {
+			return nil, fmt.Errorf("empty identifier in %q", text)
+		}
+
+		for _, character := range identifier {
+			if !(character == '-' || character >= '0' && character <= '9' || character >= 'a' && character <= 'z' || character >= 'A' && character <= 'Z') {
+				return nil, fmt.Errorf("%q can only hold [0-9A-Za-z-]", identifier)
+			}
+		}
+
+		if prerelease && isNumeric(identifier) && len(identifier) > 1 && identifier[0] == '0' {
+			return nil, fmt.Errorf("%q has a leading zero", identifier)
+		}
+	}
+
+	return identifiers, nil
+}
+
+func isNumeric(identifier string) bool {
+	for _, character := range identifier {
+		if character < '0' || character > '9' {
+			return false
+		}
+	}
+	return identifier != ""
+}
+
+// String writes the version back out, including the prefix
+func (v Version) String() string {
+	text := fmt.Sprintf("%s%d.%d.%d", v.Prefix, v.Major, v.Minor, v.Patch)
+
+	if len(v.Prerelease) > 0 {
+		text += "-" + strings.Join(v.Prerelease, ".")
+	}
+
+	if len(v.Build) > 0 {
+		text += "+" + strings.Join(v.Build, ".")
+	}
+
+	return text
+}
+
+// IsPrerelease reports whether the version has a pre-release, such as -rc.1
+func (v Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// Compare returns -1, 0 or 1 as a has lower, equal or higher precedence than b.
+// The prefix and build metadata are ignored, and a pre-release is lower than the release it leads up to.
+func Compare(a, b Version) int {
+	for _, pair := range [][2]int{{a.Major, b.Major}, {a.Minor, b.Minor}, {a.Patch, b.Patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case len(a.Prerelease) == 0 && len(b.Prerelease) == 0:
+		return 0
+	case len(a.Prerelease) == 0:
+		return 1
+	case len(b.Prerelease) == 0:
+		return -1
+	}
+
+	for index := 0; index < len(a.Prerelease) && index < len(b.Prerelease); index++ {
+		if result := compareIdentifier(a.Prerelease[index], b.Prerelease[index]); result != 0 {
+			return result
+		}
+	}
+
+	// A larger set of pre-release identifiers is higher when all the ones before are equal
+	switch {
+	case len(a.Prerelease) < len(b.Prerelease):
+		return -1
+	case len(a.Prerelease) > len(b.Prerelease):
+		return 1
+	}
+
+	return 0
+}
+
+// compareIdentifier compares numbers numerically and everything else in ASCII order, numbers are always lower
+func compareIdentifier(a, b string) int {
+	aNumeric, bNumeric := isNumeric(a), isNumeric(b)
+
+	switch {
+	case aNumeric && bNumeric:
+		aNumber, _ := strconv.Atoi(a)
+		bNumber, _ := strconv.Atoi(b)
+		switch {
+		case aNumber < bNumber:
+			return -1
+		case aNumber > bNumber:
+			return 1
+		}
+		return 0
+	case aNumeric:
+		return -1
+	case bNumeric:
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
+
+// BumpMajor returns the next major version, dropping any pre-release and build metadata.
+// A pre-release of a major version, such as 2.0.0-rc.1, becomes that version
+func (v Version) BumpMajor() Version {
+	if v.IsPrerelease() && v.Minor == 0 && v.Patch == 0 {
+		return v.Release()
+	}
+	return Version{Prefix: v.Prefix, Major: v.Major + 1}
+}
+
+// BumpMinor returns the next minor version, dropping any pre-release and build metadata.
+// A pre-release of a minor version, such as 1.3.0-rc.1, becomes that version
+func (v Version) BumpMinor() Version {
+	if v.IsPrerelease() && v.Patch == 0 {
+		return v.Release()
+	}
+	return Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor + 1}
+}
+
+// BumpPatch returns the next patch version, dropping any pre-release and build metadata.
+// A pre-release, such as 1.2.1-rc.1, becomes the version it was for
+func (v Version) BumpPatch() Version {
+	if v.IsPrerelease() {
+		return v.Release()
+	}
+	return Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+}
+
+// WithPrerelease starts the first pre-release of the version, e.g. 1.5.0 becomes 1.5.0-rc.1
+func (v Version) WithPrerelease(preid string) Version {
+	return Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor, Patch: v.Patch, Prerelease: []string{preid, "1"}}
+}
+
+// BumpPrerelease moves to the next pre-release.
+// The pre-release number goes up when the version is already a pre-release with the same preid, rc.1 becomes rc.2,
+// a different preid starts again at 1 on the same version, and a release moves on to the first pre-release of the next minor.
+func (v Version) BumpPrerelease(preid string) Version {
+	if !v.IsPrerelease() {
+		return v.BumpMinor().WithPrerelease(preid)
+	}
+
+	if v.Prerelease[0] != preid {
+		return v.WithPrerelease(preid)
+	}
+
+	next := Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor, Patch: v.Patch, Prerelease: slices.Clone(v.Prerelease)}
+
+	last := len(next.Prerelease) - 1
+	if number, err := strconv.Atoi(next.Prerelease[last]); err == nil && last > 0 {
+		next.Prerelease[last] = strconv.Itoa(number + 1)
+	} else {
+		next.Prerelease = append(next.Prerelease, "1")
+	}
+
+	return next
+}
+
+// Release drops the pre-release and build metadata, promoting 1.5.0-rc.2 to 1.5.0
+func (v Version) Release() Version {
+	return Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+}
@@ -0,0 +1,126 @@
+package semver
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Log("Testing Parse round trips valid versions")
+
+	for _, text := range []string{"1.2.3", "v1.2.3", "v1.2.3-rc.1", "v1.2.3+build5", "1.0.0-alpha.beta.1+exp.sha.5114f85", "v0.0.0"} {
+		version, err := Parse(text)
+		if err != nil {
+			t.Errorf("Parse(%q) returned %v", text, err)
+			continue
+		}
+		if version.String() != text {
+			t.Errorf("Parse(%q).String() = %q", text, version.String())
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	t.Log("Testing Parse rejects invalid versions")
+
+	for _, text := range []string{"", "v1", "1.2", "1.2.3.4", "01.2.3", "1.2.3-", "1.2.3-01", "1.2.3-rc..1", "1.2.3+", "v1.2.x", "1.2.3-rc_1", "release"} {
+		if version, err := Parse(text); err == nil {
+			t.Errorf("Parse(%q) should have failed, got %v", text, version)
+		}
+	}
+}
+
+func TestComparePrecedence(t *testing.T) {
+	t.Log("Testing Compare follows the SemVer precedence example")
+
+	// https://semver.org/#spec-item-11
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "v1.0.1", "1.1.0", "2.0.0"}
+
+	var versions []Version
+	for _, text := range ordered {
+		version, err := Parse(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		versions = append(versions, version)
+	}
+
+	shuffled := slices.Clone(versions)
+	slices.Reverse(shuffled)
+	slices.SortFunc(shuffled, Compare)
+
+	for index := range versions {
+		if shuffled[index].String() != versions[index].String() {
+			t.Errorf("position %d: got %s, want %s", index, shuffled[index], versions[index])
+		}
+	}
+}
+
+func TestCompareIgnoresBuild(t *testing.T) {
+	t.Log("Testing Compare ignores build metadata and the prefix")
+
+	a, _ := Parse("v1.2.3+build5")
+	b, _ := Parse("1.2.3+build6")
+
+	if Compare(a, b) != 0 {
+		t.Errorf("expected %s and %s to have the same precedence", a, b)
+	}
+}
+
+func TestBump(t *testing.T) {
+	t.Log("Testing the bumps keep the prefix and drop the pre-release")
+
+	cases := []struct {
+		from string
+		bump func(Version) Version
+		want string
+	}{
+		{"v1.2.3-rc.1+build5", Version.BumpMajor, "v2.0.0"},
+		{"v1.2.3-rc.1+build5", Version.BumpMinor, "v1.3.0"},
+		{"v1.2.3+build5", Version.BumpPatch, "v1.2.4"},
+		// A pre-release bumps to the version it was for when the fields below are already 0
+		{"v1.2.1-rc.1", Version.BumpPatch, "v1.2.1"},
+		{"v1.2.1-rc.1", Version.BumpMinor, "v1.3.0"},
+		{"v1.3.0-rc.1", Version.BumpMinor, "v1.3.0"},
+		{"v1.3.0-rc.1", Version.BumpPatch, "v1.3.0"},
+		{"v1.3.0-rc.1", Version.BumpMajor, "v2.0.0"},
+		{"v2.0.0-beta.1", Version.BumpMajor, "v2.0.0"},
+	}
+
+	for _, c := range cases {
+		version, err := Parse(c.from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := c.bump(version).String(); got != c.want {
+			t.Errorf("from %s got %s, want %s", c.from, got, c.want)
+		}
+	}
+}
+
+func TestPrereleaseWorkflow(t *testing.T) {
+	t.Log("Testing pre-releases and promotion")
+
+	steps := []struct {
+		from string
+		bump func(Version) Version
+		want string
+	}{
+		{"v1.4.0", func(v Version) Version { return v.BumpPrerelease("rc") }, "v1.5.0-rc.1"},
+		{"v1.5.0-rc.1", func(v Version) Version { return v.BumpPrerelease("rc") }, "v1.5.0-rc.2"},
+		{"v1.5.0-beta.3", func(v Version) Version { return v.BumpPrerelease("rc") }, "v1.5.0-rc.1"},
+		{"v1.5.0-rc", func(v Version) Version { return v.BumpPrerelease("rc") }, "v1.5.0-rc.1"},
+		{"v1.5.0-rc.2", Version.Release, "v1.5.0"},
+		{"v1.4.0", func(v Version) Version { return v.BumpMajor().WithPrerelease("beta") }, "v2.0.0-beta.1"},
+	}
+
+	for _, step := range steps {
+		version, err := Parse(step.from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := step.bump(version).String(); got != step.want {
+			t.Errorf("from %s got %s, want %s", step.from, got, step.want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package todo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CheckRuleID names the rule in SARIF output
+const CheckRuleID string = "untracked-todo"
+
+// checkMessage explains the finding the same way in every format
+func checkMessage(finding Todo) string {
+	return fmt.Sprintf("%s without an issue reference, link it with (#N): %s", finding.Marker, finding.Title)
+}
+
+// WriteFindings writes the untracked TODOs as text, GitHub Actions workflow annotations or SARIF
+func WriteFindings(w io.Writer, findings []Todo, format string) error {
+	switch format {
+	case "", "text":
+		for _, finding := range findings {
+			if _, err := fmt.Fprintf(w, "%s:%d: %s\n", finding.File, finding.Line, checkMessage(finding)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "github":
+		for _, finding := range findings {
+			_, err := fmt.Fprintf(w, "::error file=%s,line=%d,title=%s::%s\n", escapeProperty(finding.File), finding.Line, escapeProperty("Untracked "+finding.Marker), escapeData(checkMessage(finding)))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case "sarif":
+		return writeSARIF(w, findings)
+	default:
+		return fmt.Errorf("%s is not a supported format, use text, github or sarif", format)
+	}
+}
+
+// escapeData escapes a workflow command message
+// https://github.com/actions/toolkit/blob/main/packages/core/src/command.ts
+func escapeData(text string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(text)
+}
+
+// escapeProperty escapes a workflow command property, which also can't hold : or ,
+func escapeProperty(text string) string {
+	return strings.NewReplacer(":", "%3A", ",", "%2C").Replace(escapeData(text))
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name           string      `json:"name"`
+			InformationUri string      `json:"informationUri"`
+			Rules          []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifRule struct {
+	Id               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleId    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			Uri string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region struct {
+			StartLine int `json:"startLine"`
+		} `json:"region"`
+	} `json:"physicalLocation"`
+}
+
+func writeSARIF(w io.Writer, findings []Todo) error {
+	var run sarifRun
+	run.Tool.Driver.Name = "repoflow"
+	run.Tool.Driver.InformationUri = "https://github.com/jonathon-chew/go-repoflow"
+	run.Tool.Driver.Rules = []sarifRule{{Id: CheckRuleID, ShortDescription: sarifMessage{Text: "TODO without an issue reference"}}}
+	run.Results = []sarifResult{}
+
+	for _, finding := range findings {
+		var location sarifLocation
+		location.PhysicalLocation.ArtifactLocation.Uri = finding.File
+		location.PhysicalLocation.Region.StartLine = finding.Line
+
+		run.Results = append(run.Results, sarifResult{
+			RuleId:    CheckRuleID,
+			Level:     "error",
+			Message:   sarifMessage{Text: checkMessage(finding)},
+			Locations: []sarifLocation{location},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
@@ -0,0 +1,127 @@
+package todo
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var checkFindings = []Todo{
+	{Item: Item{Marker: "TODO", Title: "50% done, then: more"}, File: "internal/a,b.go", Line: 12},
+	{Item: Item{Marker: "FIXME", Title: "retry"}, File: "main.go", Line: 3},
+}
+
+func TestWriteFindings(t *testing.T) {
+	t.Log("Testing WriteFindings as text and GitHub annotations")
+
+	var out bytes.Buffer
+	if err := WriteFindings(&out, checkFindings, "text"); err != nil {
+		t.Fatal(err)
+	}
+	want := "internal/a,b.go:12: TODO without an issue reference, link it with (#N): 50% done, then: more\n" +
+		"main.go:3: FIXME without an issue reference, link it with (#N): retry\n"
+	if out.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	if err := WriteFindings(&out, checkFindings, "github"); err != nil {
+		t.Fatal(err)
+	}
+	first, _, _ := strings.Cut(out.String(), "\n")
+	if first != "::error file=internal/a%2Cb.go,line=12,title=Untracked TODO::TODO without an issue reference, link it with (#N): 50%25 done, then: more" {
+		t.Errorf("got %q", first)
+	}
+
+	if err := WriteFindings(&out, checkFindings, "junit"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestEscapeProperty(t *testing.T) {
+	t.Log("Testing the workflow command escaping")
+
+	cases := map[string]string{
+		"plain":         "plain",
+		"a:b,c":         "a%3Ab%2Cc",
+		"100%":          "100%25",
+		"line\r\nbreak": "line%0D%0Abreak",
+		"%3A is not : ": "%253A is not %3A ",
+	}
+
+	for text, want := range cases {
+		if got := escapeProperty(text); got != want {
+			t.Errorf("escapeProperty(%q) = %q, want %q", text, got, want)
+		}
+	}
+
+	if got := escapeData("a:b,c%\n"); got != "a:b,c%25%0A" {
+		t.Errorf("escapeData should leave : and , alone, got %q", got)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	t.Log("Testing the SARIF document has one run with a result for each finding")
+
+	var out bytes.Buffer
+	if err := WriteFindings(&out, checkFindings, "sarif"); err != nil {
+		t.Fatal(err)
+	}
+
+	var document struct {
+		Schema  string `json:"$schema"`
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						Id string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleId    string                `json:"ruleId"`
+				Level     string                `json:"level"`
+				Message   struct{ Text string } `json:"message"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ Uri string } `json:"artifactLocation"`
+						Region           struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &document); err != nil {
+		t.Fatal(err)
+	}
+
+	if document.Version != "2.1.0" || !strings.Contains(document.Schema, "sarif-2.1.0") || len(document.Runs) != 1 {
+		t.Fatalf("got %+v", document)
+	}
+
+	run := document.Runs[0]
+	if run.Tool.Driver.Name != "repoflow" || len(run.Tool.Driver.Rules) != 1 || run.Tool.Driver.Rules[0].Id != CheckRuleID {
+		t.Errorf("got the driver %+v", run.Tool.Driver)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(run.Results))
+	}
+
+	result := run.Results[0]
+	location := result.Locations[0].PhysicalLocation
+	if result.RuleId != CheckRuleID || result.Level != "error" || location.ArtifactLocation.Uri != "internal/a,b.go" || location.Region.StartLine != 12 {
+		t.Errorf("got %+v", result)
+	}
+
+	// Without findings there's still a run, with an empty list of results rather than null
+	out.Reset()
+	WriteFindings(&out, nil, "sarif")
+	if !strings.Contains(out.String(), `"results": []`) {
+		t.Errorf("expected an empty list of results, got:\n%s", out.String())
+	}
+}
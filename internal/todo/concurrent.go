@@ -0,0 +1,89 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// FileResult is what scanning one file found
+type FileResult struct {
+	Path    string
+	File    *File // The file as read, kept only when it has TODOs so it can be written back
+	Todos   []Todo
+	Err     error
+	Skipped error // ErrBinaryFile or ErrGeneratedFile when the file was read but its contents weren't scanned
+}
+
+// ScanFiles scans the files with a pool of workers, returning a result for each path in the same order as the paths.
+// At most workers files are read at once, runtime.NumCPU() when workers is 0 or less.
+// When the context is cancelled the workers stop picking up files and the context's error is returned.
+func ScanFiles(ctx context.Context, paths []string, workers int) ([]FileResult, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, len(paths))
+
+	// Each worker writes to its own index, so the order never depends on which worker finished first
+	results := make([]FileResult, len(paths))
+	indexes := make(chan int)
+
+	var waitGroup sync.WaitGroup
+	for range workers {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for index := range indexes {
+				results[index] = scanResult(paths[index])
+			}
+		}()
+	}
+
+	var ErrCancelled error
+
+sendLoop:
+	for index := range paths {
+		select {
+		case <-ctx.Done():
+			ErrCancelled = ctx.Err()
+			break sendLoop
+		case indexes <- index:
+		}
+	}
+
+	close(indexes)
+	waitGroup.Wait()
+
+	if ErrCancelled != nil {
+		return nil, ErrCancelled
+	}
+
+	return results, nil
+}
+
+func scanResult(path string) FileResult {
+	result := FileResult{Path: path}
+
+	// The contents are sniffed as they're read, by the worker, so a large tree is still read in parallel
+	file, err := ReadFile(path)
+	if errors.Is(err, ErrBinaryFile) || errors.Is(err, ErrGeneratedFile) {
+		result.Skipped = err
+		return result
+	}
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Todos = ScanLines(file.Lines)
+	for index := range result.Todos {
+		result.Todos[index].File = path
+	}
+
+	if len(result.Todos) > 0 {
+		result.File = file
+	}
+
+	return result
+}
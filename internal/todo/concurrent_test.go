@@ -0,0 +1,131 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// makeTree writes a tree of files where every tenth file has a TODO in it
+func makeTree(tb testing.TB, files int) []string {
+	tb.Helper()
+
+	root := tb.TempDir()
+	var paths []string
+
+	for index := range files {
+		directory := filepath.Join(root, fmt.Sprintf("pkg%03d", index/100))
+		if err := os.MkdirAll(directory, 0755); err != nil {
+			tb.Fatal(err)
+		}
+
+		var contents string
+		for line := range 200 {
+			contents += fmt.Sprintf("\tx%d := %d\n", line, line)
+		}
+		if index%10 == 0 {
+			contents += fmt.Sprintf("\t// TODO: file %d\n", index)
+		}
+
+		path := filepath.Join(directory, fmt.Sprintf("file%05d.go", index))
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			tb.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+func TestScanFilesOrder(t *testing.T) {
+	t.Log("Testing ScanFiles returns results in the order of the paths")
+
+	paths := makeTree(t, 250)
+
+	results, err := ScanFiles(context.Background(), paths, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for index, result := range results {
+		if result.Path != paths[index] {
+			t.Fatalf("result %d was for %s, want %s", index, result.Path, paths[index])
+		}
+
+		wantTodos := 0
+		if index%10 == 0 {
+			wantTodos = 1
+		}
+		if len(result.Todos) != wantTodos || (wantTodos == 0) != (result.File == nil) {
+			t.Errorf("%s: found %d TODOs, file kept %v", result.Path, len(result.Todos), result.File != nil)
+		}
+	}
+}
+
+func TestScanFilesCancelled(t *testing.T) {
+	t.Log("Testing ScanFiles stops when the context is cancelled")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ScanFiles(ctx, makeTree(t, 20), 2)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func BenchmarkScanFiles(b *testing.B) {
+	paths := makeTree(b, 2000)
+
+	for _, workers := range []int{1, 4, 0} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := ScanFiles(context.Background(), paths, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestScanFilesSkipped(t *testing.T) {
+	t.Log("Testing ScanFiles reports binary and generated files as skipped rather than failed")
+
+	directory := t.TempDir()
+	files := map[string]string{
+		"main.go":   "package main\n// TODO: keep me\n",
+		"logo.png":  "\x89PNG\r\n\x1a\n\x00\x00\x00",
+		"api.pb.go": "// Code generated by protoc-gen-go. DO NOT EDIT.\n// TODO: not ours\n",
+	}
+
+	var paths []string
+	for _, name := range []string{"main.go", "logo.png", "api.pb.go"} {
+		path := filepath.Join(directory, name)
+		if err := os.WriteFile(path, []byte(files[name]), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	results, err := ScanFiles(context.Background(), paths, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []error{nil, ErrBinaryFile, ErrGeneratedFile}
+	for index, result := range results {
+		if result.Err != nil || result.Skipped != want[index] {
+			t.Errorf("%s: got skipped %v and error %v, want skipped %v", result.Path, result.Skipped, result.Err, want[index])
+		}
+		if result.Skipped != nil && (result.File != nil || len(result.Todos) > 0) {
+			t.Errorf("%s was skipped but still scanned", result.Path)
+		}
+	}
+
+	if len(results[0].Todos) != 1 {
+		t.Errorf("expected main.go's TODO, got %+v", results[0].Todos)
+	}
+}
@@ -0,0 +1,105 @@
+package todo
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jonathon-chew/go-repoflow/internal/git"
+)
+
+// ErrBinaryFile is returned when reading a file that isn't text
+var ErrBinaryFile = errors.New("the file is binary")
+
+// ErrGeneratedFile is returned when reading a file with a "Code generated ... DO NOT EDIT." header
+var ErrGeneratedFile = errors.New("the file is generated")
+
+// sniffLength is how much of the file is looked at to decide if it's binary or generated, the same as git uses
+const sniffLength int = 8000
+
+// generatedHeaderLines is how far into the file the generated header is looked for
+const generatedHeaderLines int = 20
+
+// https://go.dev/s/generatedcode, other languages use the same line behind their own comment token
+var generatedRE = regexp.MustCompile(`^\s*(?://|#|--|;|/\*|\*)?\s*Code generated .* DO NOT EDIT\.`)
+
+// textContentTypes are the content types, other than text/*, that are still text worth scanning
+var textContentTypes = []string{"application/json", "application/javascript", "application/xml", "image/svg+xml"}
+
+// IsBinary sniffs the start of the contents, a NUL byte or a non text content type means it's binary
+func IsBinary(contents []byte) bool {
+	sniff := contents[:min(len(contents), sniffLength)]
+
+	if bytes.IndexByte(sniff, 0) >= 0 {
+		return true
+	}
+
+	contentType, _, _ := strings.Cut(http.DetectContentType(sniff), ";")
+	if strings.HasPrefix(contentType, "text/") {
+		return false
+	}
+
+	for _, textType := range textContentTypes {
+		if contentType == textType {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsGenerated looks for the "Code generated ... DO NOT EDIT." header near the start of the contents
+func IsGenerated(contents []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for line := 0; line < generatedHeaderLines && scanner.Scan(); line++ {
+		if generatedRE.Match(scanner.Bytes()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Sniff returns ErrBinaryFile or ErrGeneratedFile when the contents shouldn't be scanned for TODOs, or nil when they should.
+// Generated files would lose any change the next time they're generated
+func Sniff(contents []byte) error {
+	if IsBinary(contents) {
+		return ErrBinaryFile
+	}
+	if IsGenerated(contents) {
+		return ErrGeneratedFile
+	}
+	return nil
+}
+
+// FilterScannable drops the paths which can be ruled out without reading them: the names Skip matches
+// and those marked linguist-generated in .gitattributes. Binary and generated contents are found when the files are read.
+// Outside of a git repository nothing can be marked, so only the names are filtered.
+func FilterScannable(paths []string) []string {
+	var named []string
+	for _, path := range paths {
+		if !Skip(filepath.Base(path)) {
+			named = append(named, path)
+		}
+	}
+
+	generated, err := git.LinguistGenerated(named)
+	if err != nil || len(generated) == 0 {
+		return named
+	}
+
+	var kept []string
+	for _, path := range named {
+		if !generated[path] {
+			kept = append(kept, path)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,101 @@
+package todo
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestIsBinary(t *testing.T) {
+	t.Log("Testing IsBinary")
+
+	cases := map[string]bool{
+		"package main\n":                false,
+		"all:\n\tgo build ./...\n":      false,
+		"{\"key\": \"value\"}\n":        false,
+		"\x7fELF\x02\x01\x01\x00\x00":   true,
+		"\x89PNG\r\n\x1a\n\x00\x00\x00": true,
+		"text with a \x00 NUL in it":    true,
+		"":                              false,
+	}
+
+	for contents, want := range cases {
+		if got := IsBinary([]byte(contents)); got != want {
+			t.Errorf("IsBinary(%q) = %v, want %v", contents, got, want)
+		}
+	}
+}
+
+func TestIsGenerated(t *testing.T) {
+	t.Log("Testing IsGenerated")
+
+	cases := map[string]bool{
+		"// Code generated by protoc-gen-go. DO NOT EDIT.\npackage pb\n": true,
+		"# Code generated by a script. DO NOT EDIT.\nx = 1\n":            true,
+		"package main\n\n// Code generated is mentioned here\n":          false,
+	}
+
+	for contents, want := range cases {
+		if got := IsGenerated([]byte(contents)); got != want {
+			t.Errorf("IsGenerated(%q) = %v, want %v", contents, got, want)
+		}
+	}
+}
+
+func TestFilterScannable(t *testing.T) {
+	t.Log("Testing FilterScannable drops skipped names and linguist-generated paths")
+
+	t.Chdir(t.TempDir())
+	if out, err := exec.Command("git", "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %s", out)
+	}
+	if err := os.WriteFile(".gitattributes", []byte("gen/** linguist-generated\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	paths := []string{"README.md", "gen/api.go", "main.go", "logo.png", filepath.Join("internal", ".DS_Store")}
+
+	// Binary and generated contents are left for ReadFile to find, as the files are read
+	want := []string{"main.go", "logo.png"}
+	if got := FilterScannable(paths); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSniff(t *testing.T) {
+	t.Log("Testing Sniff")
+
+	cases := map[string]error{
+		"package main\n// TODO: keep me\n":                                      nil,
+		"\x89PNG\r\n\x1a\n\x00\x00\x00":                                         ErrBinaryFile,
+		"// Code generated by protoc-gen-go. DO NOT EDIT.\n// TODO: not ours\n": ErrGeneratedFile,
+	}
+
+	for contents, want := range cases {
+		if got := Sniff([]byte(contents)); got != want {
+			t.Errorf("Sniff(%q) = %v, want %v", contents, got, want)
+		}
+	}
+}
+
+func TestSkip(t *testing.T) {
+	t.Log("Testing Skip matches whole extensions")
+
+	cases := map[string]bool{
+		"README.md":  true,
+		"page.mdx":   false,
+		"Makefile":   false,
+		"Dockerfile": false,
+		".DS_Store":  true,
+		"tool.EXE":   true,
+		"main.go":    false,
+	}
+
+	for name, want := range cases {
+		if got := Skip(name); got != want {
+			t.Errorf("Skip(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
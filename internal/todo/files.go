@@ -0,0 +1,58 @@
+package todo
+
+import (
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// UnwantedFiles are never scanned for TODOs
+var UnwantedFiles = []string{".localized", ".DS_Store", ".gitignore"}
+
+// UnwantedExtentions are the binary and documentation files that are never scanned for TODOs, other binary files are found by their contents
+var UnwantedExtentions = []string{".app", ".exe", ".elf", ".md"}
+
+// skippedDirectories are never walked into when looking for files
+var skippedDirectories = []string{".git", "node_modules", "vendor"}
+
+// Skip reports whether the file name shouldn't be scanned for TODOs, files without an extension such as Makefile are scanned
+func Skip(name string) bool {
+	if slices.Contains(UnwantedFiles, name) {
+		return true
+	}
+
+	return slices.Contains(UnwantedExtentions, strings.ToLower(filepath.Ext(name)))
+}
+
+// FindFiles walks the tree under root and returns every file that should be scanned for TODOs, in lexical order.
+// Files marked linguist-generated are left out, binary and generated contents are only found when they are read, see ReadFile.
+func FindFiles(root string) ([]string, error) {
+	var files []string
+
+	ErrWalking := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if path != root && slices.Contains(skippedDirectories, entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !entry.Type().IsRegular() || Skip(entry.Name()) {
+			return nil
+		}
+
+		files = append(files, path)
+		return nil
+	})
+
+	if ErrWalking != nil {
+		return nil, ErrWalking
+	}
+
+	return FilterScannable(files), nil
+}
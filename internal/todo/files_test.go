@@ -0,0 +1,42 @@
+package todo
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFindFiles(t *testing.T) {
+	t.Log("Testing FindFiles skips unwanted files and folders")
+
+	root := t.TempDir()
+	files := map[string]string{
+		"main.go":                   "package main\n",
+		"Makefile":                  "build:\n",
+		"README.md":                 "# Docs\n",
+		".DS_Store":                 "",
+		"internal/cli/cli.go":       "package cli\n",
+		"node_modules/lib/index.js": "// TODO: not ours\n",
+		"vendor/dep/dep.go":         "package dep\n",
+		".git/config":               "[core]\n",
+	}
+	for name, contents := range files {
+		path := filepath.Join(root, name)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		os.WriteFile(path, []byte(contents), 0o644)
+	}
+
+	found, err := FindFiles(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"Makefile", "internal/cli/cli.go", "main.go"}
+	for index := range want {
+		want[index] = filepath.Join(root, want[index])
+	}
+	if !slices.Equal(found, want) {
+		t.Errorf("got %v, want %v", found, want)
+	}
+}
@@ -0,0 +1,230 @@
+package todo
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DueDateLayout is the format expected after due: in the TODO metadata
+const DueDateLayout string = "2006-01-02"
+
+// Markers are the words which start a TODO comment
+var Markers = []string{"TODO", "FIXME"}
+
+// commentTokens are the ways a comment can start, the longest tokens come first so /* isn't read as *
+var commentTokens = []string{"<!--", "\"\"\"", "'''", "//", "/*", "--", "#", ";", "*", "%"}
+
+var issueRefRE = regexp.MustCompile(`\(#(\d+)\)\s*$`)
+var priorityRE = regexp.MustCompile(`^[pP]([0-9])$`)
+
+// Item is a single TODO found on a line of a file
+type Item struct {
+	Marker    string    // TODO or FIXME
+	Title     string    // The text of the TODO without the comment markers or metadata
+	Issue     int       // The linked issue number, 0 when the TODO hasn't been raised yet
+	Assignees []string  // From @username in the metadata
+	Labels    []string  // From #label in the metadata
+	Priority  string    // From p0 - p9 in the metadata, always upper case
+	Due       time.Time // From due:YYYY-MM-DD in the metadata
+	Indent    string    // The white space at the start of the line
+	Comment   string    // The comment token before the marker, empty when the marker starts the line
+	Column    int       // The byte offset of the marker (or the issue reference before it) in the line
+}
+
+// PriorityLabel is the label used on the issue for the priority, empty when no priority was given
+func (i Item) PriorityLabel() string {
+	if i.Priority == "" {
+		return ""
+	}
+	return "priority:" + strings.ToLower(i.Priority)
+}
+
+// Parse looks for a TODO or FIXME comment in the line.
+// Both "TODO: text" and "TODO(@user, #label, p1, due:2026-12-01): text" are understood, and a
+// "(#12)" directly before the marker is read as the linked issue number.
+// The marker has to start the line or follow a comment token, so strings in code aren't picked up.
+func Parse(line string) (Item, bool) {
+	var item Item
+
+	for searchFrom := 0; searchFrom < len(line); {
+		marker, column := findMarker(line, searchFrom)
+		if marker == "" {
+			return item, false
+		}
+
+		// Try the next occurrence if this one isn't a comment
+		if parsed, ok := parseAt(line, marker, column); ok {
+			return parsed, true
+		}
+		searchFrom = column + len(marker)
+	}
+
+	return item, false
+}
+
+// findMarker returns the earliest whole word marker after the offset
+func findMarker(line string, from int) (string, int) {
+	var found string
+	var foundAt int = -1
+
+	for _, marker := range Markers {
+		for offset := from; offset < len(line); {
+			index := strings.Index(line[offset:], marker)
+			if index < 0 {
+				break
+			}
+			index += offset
+			if isWordBoundary(line, index-1) && isWordBoundary(line, index+len(marker)) {
+				if foundAt < 0 || index < foundAt {
+					found, foundAt = marker, index
+				}
+				break
+			}
+			offset = index + len(marker)
+		}
+	}
+
+	return found, foundAt
+}
+
+func isWordBoundary(line string, index int) bool {
+	if index < 0 || index >= len(line) {
+		return true
+	}
+	character := line[index]
+	return !(character == '_' || character >= 'a' && character <= 'z' || character >= 'A' && character <= 'Z' || character >= '0' && character <= '9')
+}
+
+func parseAt(line, marker string, column int) (Item, bool) {
+	var item Item
+	item.Marker = marker
+	item.Column = column
+
+	prefix := line[:column]
+
+	// A (#12) in front of the marker links it to an existing issue
+	if match := issueRefRE.FindStringSubmatchIndex(prefix); match != nil {
+		item.Issue, _ = strconv.Atoi(prefix[match[2]:match[3]])
+		item.Column = match[0]
+		prefix = prefix[:match[0]]
+	}
+
+	trimmedPrefix := strings.TrimRight(prefix, " \t")
+	item.Indent = line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+
+	if strings.TrimSpace(trimmedPrefix) != "" {
+		for _, token := range commentTokens {
+			if strings.HasSuffix(trimmedPrefix, token) {
+				item.Comment = token
+				break
+			}
+		}
+		if item.Comment == "" || insideString(strings.TrimSuffix(trimmedPrefix, item.Comment)) {
+			return item, false
+		}
+	}
+
+	rest := line[column+len(marker):]
+
+	// Metadata in brackets straight after the marker
+	if strings.HasPrefix(rest, "(") {
+		closing := strings.Index(rest, ")")
+		if closing < 0 {
+			return item, false
+		}
+		parseMetadata(&item, rest[1:closing])
+		rest = rest[closing+1:]
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return item, false
+	}
+
+	item.Title = cleanTitle(rest[1:])
+
+	return item, true
+}
+
+// insideString reports whether the code ends part way through a quoted string, so a comment token after it is really text
+func insideString(code string) bool {
+	var quote byte
+
+	escaped := false
+	for index := 0; index < len(code); index++ {
+		character := code[index]
+		switch {
+		case escaped:
+			escaped = false
+		case character == '\\' && quote != '`':
+			escaped = true
+		case quote == 0 && character == '\'' && isLifetime(code, index):
+			// A Rust lifetime or loop label such as 'a never closes, so it isn't the start of a string
+		case quote == 0 && (character == '"' || character == '\'' || character == '`'):
+			quote = character
+		case character == quote:
+			quote = 0
+		}
+	}
+
+	return quote != 0
+}
+
+// isLifetime reports whether the ' at index starts a Rust lifetime or label, such as &'a, <'a>, 'static or break 'outer;
+// rather than a character literal like 'a' or a single quoted string
+func isLifetime(code string, index int) bool {
+	end := index + 1
+	for end < len(code) && (code[end] == '_' || 'a' <= code[end] && code[end] <= 'z' || 'A' <= code[end] && code[end] <= 'Z' || '0' <= code[end] && code[end] <= '9') {
+		end++
+	}
+
+	name := code[index+1 : end]
+	if name == "" || (end < len(code) && code[end] == '\'') {
+		return false
+	}
+
+	if name == "static" || (index > 0 && (code[index-1] == '&' || code[index-1] == '<')) {
+		return true
+	}
+
+	// What follows a lifetime in generics, bounds and labels, where a string would carry on with text
+	return end < len(code) && strings.ContainsRune(">,:;)", rune(code[end]))
+}
+
+func parseMetadata(item *Item, metadata string) {
+	for _, field := range strings.FieldsFunc(metadata, func(r rune) bool { return r == ',' || r == ' ' }) {
+		switch {
+		case strings.HasPrefix(field, "@") && len(field) > 1:
+			item.Assignees = append(item.Assignees, field[1:])
+		case strings.HasPrefix(field, "#") && len(field) > 1:
+			// A number is a reference to an issue rather than a label
+			if number, ErrConv := strconv.Atoi(field[1:]); ErrConv == nil {
+				item.Issue = number
+			} else {
+				item.Labels = append(item.Labels, field[1:])
+			}
+		case priorityRE.MatchString(field):
+			item.Priority = strings.ToUpper(field)
+		case strings.HasPrefix(strings.ToLower(field), "due:"):
+			due, ErrParsingDate := time.Parse(DueDateLayout, field[len("due:"):])
+			if ErrParsingDate == nil {
+				item.Due = due
+			}
+		}
+	}
+}
+
+// cleanTitle removes the white space and any closing comment token from the text
+func cleanTitle(text string) string {
+	text = strings.TrimSpace(text)
+	for _, closing := range []string{"*/", "-->", "\"\"\"", "'''"} {
+		text = strings.TrimSpace(strings.TrimSuffix(text, closing))
+	}
+	return text
+}
+
+// Link adds the issue reference in front of the marker, so the TODO isn't raised a second time
+func Link(line string, item Item, number int) string {
+	return line[:item.Column] + "(#" + strconv.Itoa(number) + ") " + line[item.Column:]
+}
@@ -0,0 +1,113 @@
+package todo
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParsePlainTodo(t *testing.T) {
+	t.Log("Testing Parse with a plain TODO")
+
+	item, ok := Parse("\t// TODO: speed up the cache")
+	if !ok {
+		t.Fatal("expected a TODO to be found")
+	}
+
+	if item.Marker != "TODO" || item.Title != "speed up the cache" || item.Comment != "//" || item.Indent != "\t" {
+		t.Errorf("unexpected item %+v", item)
+	}
+}
+
+func TestParseMetadata(t *testing.T) {
+	t.Log("Testing Parse with metadata in brackets")
+
+	item, ok := Parse("# TODO(@alice, #perf, p1, due:2026-12-01): speed up cache")
+	if !ok {
+		t.Fatal("expected a TODO to be found")
+	}
+
+	if item.Title != "speed up cache" {
+		t.Errorf("title was %q", item.Title)
+	}
+	if !slices.Equal(item.Assignees, []string{"alice"}) {
+		t.Errorf("assignees were %v", item.Assignees)
+	}
+	if !slices.Equal(item.Labels, []string{"perf"}) {
+		t.Errorf("labels were %v", item.Labels)
+	}
+	if item.Priority != "P1" || item.PriorityLabel() != "priority:p1" {
+		t.Errorf("priority was %q", item.Priority)
+	}
+	if !item.Due.Equal(time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("due date was %v", item.Due)
+	}
+}
+
+func TestParseLinkedIssue(t *testing.T) {
+	t.Log("Testing Parse reads linked issues")
+
+	cases := map[string]int{
+		"// (#22) TODO: close the issue": 22,
+		"// TODO(#7): close the issue":   7,
+		"/* FIXME: nothing linked */":    0,
+	}
+
+	for line, want := range cases {
+		item, ok := Parse(line)
+		if !ok {
+			t.Errorf("expected a TODO in %q", line)
+			continue
+		}
+		if item.Issue != want {
+			t.Errorf("Parse(%q).Issue = %d, want %d", line, item.Issue, want)
+		}
+	}
+}
+
+func TestParseIgnoresCode(t *testing.T) {
+	t.Log("Testing Parse ignores markers outside of comments")
+
+	for _, line := range []string{
+		`if strings.Contains(line, "TODO: ") {`,
+		"// This finds OLD TODOs",
+		"// TODOS: not a marker",
+		"var TODO = 1",
+		`	"// TODO: inside a string",`,
+		"	x := '\"' + \"// TODO: still a string\"",
+		"	s = 'hello // TODO: inside a python string'",
+		"	c := 'x' + \"// TODO: after a character literal\"",
+	} {
+		if item, ok := Parse(line); ok {
+			t.Errorf("did not expect a TODO in %q, got %+v", line, item)
+		}
+	}
+}
+
+func TestParseAfterRustLifetime(t *testing.T) {
+	t.Log("Testing a Rust lifetime doesn't hide the comment after it")
+
+	for _, line := range []string{
+		"fn first(x: &'a str) -> &str { // TODO: drop the lifetime",
+		"impl<'a> Parser<'a> { // TODO: borrow less",
+		"static NAME: &'static str = \"x\"; // TODO: read from config",
+		"    break 'outer; // TODO: explain the label",
+		"fn f<'a, T: 'a>() {} // TODO: simplify the bounds",
+	} {
+		item, ok := Parse(line)
+		if !ok || item.Comment != "//" {
+			t.Errorf("expected a TODO in %q, got %+v", line, item)
+		}
+	}
+}
+
+func TestLink(t *testing.T) {
+	t.Log("Testing Link adds the issue number before the marker")
+
+	line := "x := 1 // TODO(@bob): tidy up"
+	item, _ := Parse(line)
+
+	if got := Link(line, item, 12); got != "x := 1 // (#12) TODO(@bob): tidy up" {
+		t.Errorf("Link returned %q", got)
+	}
+}
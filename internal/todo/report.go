@@ -0,0 +1,148 @@
+package todo
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ReportEntry is a TODO along with who wrote it and how long ago
+type ReportEntry struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Marker  string `json:"marker"`
+	Issue   int    `json:"issue,omitempty"`
+	Author  string `json:"author,omitempty"`
+	AgeDays int    `json:"age_days"`
+	Title   string `json:"title"`
+}
+
+// ReportGroup is the entries that share a directory or author
+type ReportGroup struct {
+	Name  string        `json:"group"`
+	Todos []ReportEntry `json:"todos"`
+}
+
+// GroupEntries splits the entries by "directory" or "author", groups are sorted by name and keep the order of their entries
+func GroupEntries(entries []ReportEntry, groupBy string) ([]ReportGroup, error) {
+	var groups []ReportGroup
+	indexes := map[string]int{}
+
+	for _, entry := range entries {
+		var name string
+		switch groupBy {
+		case "directory", "dir":
+			name = filepath.Dir(entry.File)
+		case "author":
+			name = entry.Author
+			if name == "" {
+				name = "unknown"
+			}
+		default:
+			return nil, fmt.Errorf("%s is not a way to group TODOs, use directory or author", groupBy)
+		}
+
+		index, ok := indexes[name]
+		if !ok {
+			index = len(groups)
+			indexes[name] = index
+			groups = append(groups, ReportGroup{Name: name})
+		}
+		groups[index].Todos = append(groups[index].Todos, entry)
+	}
+
+	slices.SortStableFunc(groups, func(a, b ReportGroup) int { return strings.Compare(a.Name, b.Name) })
+
+	return groups, nil
+}
+
+// WriteReport writes the groups in the format asked for, one of text, markdown, json or csv
+func WriteReport(w io.Writer, groups []ReportGroup, format string) error {
+	switch format {
+	case "", "text":
+		return writeText(w, groups)
+	case "markdown", "md":
+		return writeMarkdown(w, groups)
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if groups == nil {
+			groups = []ReportGroup{}
+		}
+		return encoder.Encode(groups)
+	case "csv":
+		return writeCSV(w, groups)
+	default:
+		return fmt.Errorf("%s is not a supported format, use markdown, json or csv", format)
+	}
+}
+
+func issueText(issue int) string {
+	if issue == 0 {
+		return "-"
+	}
+	return "#" + strconv.Itoa(issue)
+}
+
+func authorText(author string) string {
+	if author == "" {
+		return "unknown"
+	}
+	return author
+}
+
+func writeText(w io.Writer, groups []ReportGroup) error {
+	for _, group := range groups {
+		if _, err := fmt.Fprintf(w, "%s\n", group.Name); err != nil {
+			return err
+		}
+		for _, entry := range group.Todos {
+			_, err := fmt.Fprintf(w, "  %s:%d %s %s %s %dd %s\n", entry.File, entry.Line, entry.Marker, issueText(entry.Issue), authorText(entry.Author), entry.AgeDays, entry.Title)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeMarkdown(w io.Writer, groups []ReportGroup) error {
+	for index, group := range groups {
+		if index > 0 {
+			fmt.Fprintf(w, "\n")
+		}
+		fmt.Fprintf(w, "## %s\n\n", group.Name)
+		fmt.Fprintf(w, "| File | Line | Marker | Issue | Author | Age (days) | Title |\n")
+		fmt.Fprintf(w, "| --- | --- | --- | --- | --- | --- | --- |\n")
+		for _, entry := range group.Todos {
+			_, err := fmt.Fprintf(w, "| %s | %d | %s | %s | %s | %d | %s |\n", entry.File, entry.Line, entry.Marker, issueText(entry.Issue), authorText(entry.Author), entry.AgeDays, strings.ReplaceAll(entry.Title, "|", "\\|"))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeCSV(w io.Writer, groups []ReportGroup) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"group", "file", "line", "marker", "issue", "author", "age_days", "title"})
+
+	for _, group := range groups {
+		for _, entry := range group.Todos {
+			var issue string
+			if entry.Issue != 0 {
+				issue = strconv.Itoa(entry.Issue)
+			}
+			writer.Write([]string{group.Name, entry.File, strconv.Itoa(entry.Line), entry.Marker, issue, entry.Author, strconv.Itoa(entry.AgeDays), entry.Title})
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,112 @@
+package todo
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var reportEntries = []ReportEntry{
+	{File: "internal/cli/cli.go", Line: 10, Marker: "TODO", Issue: 4, Author: "Alice", AgeDays: 3, Title: "tidy the flags"},
+	{File: "main.go", Line: 2, Marker: "FIXME", Title: "handle | in titles, and \"quotes\""},
+	{File: "internal/cli/tag.go", Line: 7, Marker: "TODO", Author: "Bob", AgeDays: 40, Title: "split this up"},
+}
+
+func TestGroupEntries(t *testing.T) {
+	t.Log("Testing GroupEntries by directory and author")
+
+	byDirectory, err := GroupEntries(reportEntries, "directory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byDirectory) != 2 || byDirectory[0].Name != "." || byDirectory[1].Name != "internal/cli" {
+		t.Fatalf("got %+v", byDirectory)
+	}
+	if len(byDirectory[1].Todos) != 2 || byDirectory[1].Todos[0].Line != 10 || byDirectory[1].Todos[1].Line != 7 {
+		t.Errorf("the entries should keep their order within a group, got %+v", byDirectory[1].Todos)
+	}
+
+	byAuthor, err := GroupEntries(reportEntries, "author")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, group := range byAuthor {
+		names = append(names, group.Name)
+	}
+	if strings.Join(names, ",") != "Alice,Bob,unknown" {
+		t.Errorf("got the groups %v", names)
+	}
+
+	if _, err := GroupEntries(reportEntries, "file"); err == nil {
+		t.Error("expected an error for an unknown grouping")
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	t.Log("Testing WriteReport in each format")
+
+	groups, _ := GroupEntries(reportEntries, "directory")
+
+	formats := map[string][]string{
+		"text": {
+			".\n  main.go:2 FIXME - unknown 0d handle | in titles",
+			"internal/cli\n  internal/cli/cli.go:10 TODO #4 Alice 3d tidy the flags\n",
+		},
+		"markdown": {
+			"## .\n\n| File | Line | Marker | Issue | Author | Age (days) | Title |\n| --- |",
+			"| main.go | 2 | FIXME | - | unknown | 0 | handle \\| in titles, and \"quotes\" |\n",
+			"\n## internal/cli\n",
+		},
+	}
+
+	for format, wants := range formats {
+		var out bytes.Buffer
+		if err := WriteReport(&out, groups, format); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("%s: expected %q in:\n%s", format, want, out.String())
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	if err := WriteReport(&out, groups, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []ReportGroup
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || len(decoded) != 2 || decoded[1].Todos[0].Issue != 4 {
+		t.Errorf("the JSON didn't round trip: %v %+v", err, decoded)
+	}
+
+	out.Reset()
+	if err := WriteReport(&out, nil, "json"); err != nil || strings.TrimSpace(out.String()) != "[]" {
+		t.Errorf("no TODOs should be an empty list, got %q", out.String())
+	}
+
+	out.Reset()
+	if err := WriteReport(&out, groups, "csv"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"handle | in titles, and ""quotes"""`) {
+		t.Errorf("the title should be quoted, got:\n%s", out.String())
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 || strings.Join(records[0], ",") != "group,file,line,marker,issue,author,age_days,title" {
+		t.Fatalf("got %v", records)
+	}
+	if records[1][7] != `handle | in titles, and "quotes"` || records[1][4] != "" || records[2][4] != "4" {
+		t.Errorf("got the rows %v", records[1:])
+	}
+
+	if err := WriteReport(&out, groups, "yaml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
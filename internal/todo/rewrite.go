@@ -0,0 +1,142 @@
+package todo
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUnsupportedEncoding is returned for files with a UTF-16 or UTF-32 byte order mark, which are left alone
+var ErrUnsupportedEncoding = errors.New("the file is not UTF-8 encoded")
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// File is a text file split into lines, keeping everything needed to write it back byte for byte
+type File struct {
+	Path    string
+	Lines   []string    // The lines without their line endings
+	endings []string    // The line ending after each line, "\n", "\r\n" or "" for a last line with no new line
+	bom     bool        // The file started with a UTF-8 byte order mark
+	mode    os.FileMode // The permissions to keep when writing
+}
+
+// ReadFile reads the whole file into lines, there is no limit on how long a line can be.
+// Binary and generated files return ErrBinaryFile and ErrGeneratedFile, so they are never rewritten.
+func ReadFile(path string) (*File, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.HasPrefix(contents, []byte{0xFE, 0xFF}) || bytes.HasPrefix(contents, []byte{0xFF, 0xFE}) {
+		return nil, ErrUnsupportedEncoding
+	}
+
+	if err := Sniff(contents[:min(len(contents), sniffLength)]); err != nil {
+		return nil, err
+	}
+
+	file := &File{Path: path, mode: info.Mode().Perm()}
+
+	if bytes.HasPrefix(contents, utf8BOM) {
+		file.bom = true
+		contents = contents[len(utf8BOM):]
+	}
+
+	text := string(contents)
+	for text != "" {
+		line, rest, foundNewLine := strings.Cut(text, "\n")
+
+		ending := ""
+		if foundNewLine {
+			ending = "\n"
+			if strings.HasSuffix(line, "\r") {
+				line, ending = line[:len(line)-1], "\r\n"
+			}
+		}
+
+		file.Lines = append(file.Lines, line)
+		file.endings = append(file.endings, ending)
+		text = rest
+	}
+
+	return file, nil
+}
+
+// Bytes puts the file back together with its original line endings and byte order mark
+func (f *File) Bytes() []byte {
+	var buffer bytes.Buffer
+
+	if f.bom {
+		buffer.Write(utf8BOM)
+	}
+
+	for index, line := range f.Lines {
+		buffer.WriteString(line)
+
+		// Lines added past the end of the original file use the ending of the line before them
+		switch {
+		case index < len(f.endings):
+			buffer.WriteString(f.endings[index])
+		case index > 0:
+			buffer.WriteString(f.lineEnding())
+		}
+	}
+
+	return buffer.Bytes()
+}
+
+// lineEnding is the ending used by the first line which has one
+func (f *File) lineEnding() string {
+	for _, ending := range f.endings {
+		if ending != "" {
+			return ending
+		}
+	}
+	return "\n"
+}
+
+// Write replaces the file atomically, writing to a temporary file next to it and renaming it over the original
+func (f *File) Write() error {
+	// Write through symlinks rather than replacing them with a file
+	path, err := filepath.EvalSymlinks(f.Path)
+	if err != nil {
+		return err
+	}
+
+	temporaryFile, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".repoflow-*")
+	if err != nil {
+		return err
+	}
+
+	// Make sure the temporary file doesn't get left behind if anything goes wrong
+	temporaryPath := temporaryFile.Name()
+	defer os.Remove(temporaryPath)
+
+	if _, err := temporaryFile.Write(f.Bytes()); err != nil {
+		temporaryFile.Close()
+		return err
+	}
+
+	if err := temporaryFile.Sync(); err != nil {
+		temporaryFile.Close()
+		return err
+	}
+
+	if err := temporaryFile.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(temporaryPath, f.mode); err != nil {
+		return err
+	}
+
+	return os.Rename(temporaryPath, path)
+}
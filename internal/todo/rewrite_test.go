@@ -0,0 +1,100 @@
+package todo
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileRoundTrip(t *testing.T) {
+	t.Log("Testing ReadFile and Write keep the file byte for byte")
+
+	cases := map[string]string{
+		"crlf":       "one\r\ntwo\r\n",
+		"no-newline": "one\ntwo",
+		"mixed":      "one\r\ntwo\nthree",
+		"bom":        "\xEF\xBB\xBF// TODO: bom\n",
+		"long-line":  strings.Repeat("x", 100*1024) + "\n",
+		"empty":      "",
+	}
+
+	directory := t.TempDir()
+
+	for name, contents := range cases {
+		path := filepath.Join(directory, name)
+		if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		file, err := ReadFile(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if err := file.Write(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		written, _ := os.ReadFile(path)
+		if !bytes.Equal(written, []byte(contents)) {
+			t.Errorf("%s: the file changed to %q", name, written)
+		}
+
+		info, _ := os.Stat(path)
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("%s: the mode changed to %v", name, info.Mode().Perm())
+		}
+	}
+}
+
+func TestFileWriteChangedLine(t *testing.T) {
+	t.Log("Testing a changed line keeps its CRLF ending")
+
+	path := filepath.Join(t.TempDir(), "main.go")
+	os.WriteFile(path, []byte("\xEF\xBB\xBF// TODO: one\r\n// two\r\n"), 0644)
+
+	file, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if file.Lines[0] != "// TODO: one" {
+		t.Errorf("the first line was read as %q", file.Lines[0])
+	}
+
+	file.Lines[0] = "// (#1) TODO: one"
+	if err := file.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	written, _ := os.ReadFile(path)
+	if string(written) != "\xEF\xBB\xBF// (#1) TODO: one\r\n// two\r\n" {
+		t.Errorf("the file was written as %q", written)
+	}
+}
+
+func TestReadFileRefusesBinary(t *testing.T) {
+	t.Log("Testing ReadFile won't read a binary or generated file, so it can't be written back")
+
+	directory := t.TempDir()
+	cases := map[string]struct {
+		contents string
+		want     error
+	}{
+		"logo.png":  {"\x89PNG\r\n\x1a\n\x00\x00\x00", ErrBinaryFile},
+		"api.pb.go": {"// Code generated by protoc-gen-go. DO NOT EDIT.\npackage pb\n", ErrGeneratedFile},
+	}
+
+	for name, c := range cases {
+		path := filepath.Join(directory, name)
+		if err := os.WriteFile(path, []byte(c.contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadFile(path); !errors.Is(err, c.want) {
+			t.Errorf("ReadFile(%s) returned %v, want %v", name, err, c.want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package todo
+
+import "strings"
+
+// blockComments pairs the token which opens a block comment with the token which closes it
+var blockComments = [][2]string{
+	{"/*", "*/"},
+	{`"""`, `"""`},
+	{"'''", "'''"},
+}
+
+// Todo is an Item along with where it was found and the comment lines that carry on from it
+type Todo struct {
+	Item
+	File string // The path the TODO was found in, empty when scanning lines
+	Line int    // The line number of the TODO, starting at 1
+	Body string // The comment lines following the TODO, joined with new lines
+}
+
+// ScanFile reads the file and returns every TODO in it
+func ScanFile(path string) ([]Todo, error) {
+	file, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	todos := ScanLines(file.Lines)
+	for index := range todos {
+		todos[index].File = path
+	}
+
+	return todos, nil
+}
+
+// ScanLines returns every TODO in the lines, in order.
+// The comment lines straight after a TODO with the same indentation and comment style become its body,
+// and inside a block comment or docstring the rest of the block does, stopping at an empty line or another TODO.
+func ScanLines(lines []string) []Todo {
+	var todos []Todo
+
+	// The token that closes the block comment we're in, empty when outside of one
+	var closeBlock string
+
+	for index, line := range lines {
+		inBlock := closeBlock
+		closeBlock = trackBlock(line, closeBlock)
+
+		item, ok := Parse(line)
+		if !ok {
+			continue
+		}
+
+		found := Todo{Item: item, Line: index + 1}
+
+		var body []string
+		switch {
+		case inBlock != "" || closeBlock != "":
+			// The TODO is in, or opens, a block comment
+			if closeBlock != "" {
+				body = blockBody(lines[index+1:], closeBlock)
+			}
+		case strings.TrimSpace(line[:item.Column]) == item.Comment && item.Comment != "":
+			// The TODO is a line comment of its own, not at the end of some code
+			body = lineCommentBody(lines[index+1:], item.Indent+item.Comment)
+		}
+
+		found.Body = strings.Join(body, "\n")
+		todos = append(todos, found)
+	}
+
+	return todos
+}
+
+// trackBlock works out whether the line leaves us inside a block comment, returning the closing token if so
+func trackBlock(line, closeBlock string) string {
+	for rest := line; rest != ""; {
+		if closeBlock != "" {
+			index := strings.Index(rest, closeBlock)
+			if index < 0 {
+				return closeBlock
+			}
+			rest = rest[index+len(closeBlock):]
+			closeBlock = ""
+			continue
+		}
+
+		// Most lines can't open a block comment at all
+		if !strings.ContainsAny(rest, "/\"'") {
+			return ""
+		}
+
+		// Find the earliest opening token left in the line
+		var opening [2]string
+		var openAt int = -1
+		for _, pair := range blockComments {
+			index := strings.Index(rest, pair[0])
+			if index >= 0 && (openAt < 0 || index < openAt) {
+				opening, openAt = pair, index
+			}
+		}
+
+		if openAt < 0 {
+			return ""
+		}
+
+		closeBlock = opening[1]
+		rest = rest[openAt+len(opening[0]):]
+	}
+
+	return closeBlock
+}
+
+// lineCommentBody collects the lines that start with exactly the same indentation and comment token
+func lineCommentBody(lines []string, prefix string) []string {
+	var body []string
+
+	for _, line := range lines {
+		if !strings.HasPrefix(line, prefix) {
+			break
+		}
+
+		text := strings.TrimSpace(line[len(prefix):])
+		if text == "" {
+			break
+		}
+		if _, isTODO := Parse(line); isTODO {
+			break
+		}
+
+		body = append(body, text)
+	}
+
+	return body
+}
+
+// blockBody collects the lines up to the end of the block comment, dropping any leading * and the closing token
+func blockBody(lines []string, closeBlock string) []string {
+	var body []string
+
+	for _, line := range lines {
+		if _, isTODO := Parse(line); isTODO {
+			break
+		}
+
+		text, _, closed := strings.Cut(strings.TrimSpace(line), closeBlock)
+
+		if closeBlock == "*/" {
+			text = strings.TrimPrefix(strings.TrimSpace(text), "*")
+		}
+		text = strings.TrimSpace(text)
+
+		if text == "" {
+			break
+		}
+
+		body = append(body, text)
+
+		if closed {
+			break
+		}
+	}
+
+	return body
+}
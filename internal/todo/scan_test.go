@@ -0,0 +1,52 @@
+package todo
+
+import "testing"
+
+func TestScanLinesBody(t *testing.T) {
+	t.Log("Testing ScanLines collects the comment lines after a TODO")
+
+	lines := []string{
+		"func main() {",
+		"	// TODO: speed up the cache",
+		"	// the lookups are linear",
+		"	// so large repos are slow",
+		"	//",
+		"	// not part of the body",
+		"	x := 1 // FIXME: trailing comment",
+		"	// not a continuation of the trailing comment",
+		"	/* TODO: block comment",
+		"	 * with a second line",
+		"	 */",
+		"	\"\"\"",
+		"	TODO: docstring",
+		"	more detail",
+		"	\"\"\"",
+		"	'''",
+		"	TODO: single quoted docstring",
+		"	with its own detail",
+		"	'''",
+		"}",
+	}
+
+	todos := ScanLines(lines)
+	if len(todos) != 5 {
+		t.Fatalf("expected 5 TODOs, found %d", len(todos))
+	}
+
+	want := []struct {
+		line int
+		body string
+	}{
+		{2, "the lookups are linear\nso large repos are slow"},
+		{7, ""},
+		{9, "with a second line"},
+		{13, "more detail"},
+		{17, "with its own detail"},
+	}
+
+	for index, expected := range want {
+		if todos[index].Line != expected.line || todos[index].Body != expected.body {
+			t.Errorf("todo %d: got line %d body %q, want line %d body %q", index, todos[index].Line, todos[index].Body, expected.line, expected.body)
+		}
+	}
+}
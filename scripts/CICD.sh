@@ -3,10 +3,15 @@
 set -euo pipefail
 
 dry="0"
+bump="patch"
 
 while [[ $# > 0 ]]; do
   if [[ $1 == "--dry" ]]; then
     dry="1"
+  elif [[ $1 == "--bump" && $# > 1 ]]; then
+    # major, minor or patch, for the tag made in step 5
+    bump="$2"
+    shift
   fi
   shift
 done
@@ -35,7 +40,7 @@ fi
 # Step 2: Build all packages
 # ----------------------------
 echo -e "${CYAN}🛠 Building all packages...${RESET}"
-if go build -o repoflow ./cmd/repoflow/main.go; then
+if go build ./...; then
   echo -e "${GREEN} Build succeeded!${RESET}"
 else
   echo -e "${RED} Build failed!${RESET}"
@@ -78,7 +83,8 @@ fi
 # ----------------------------
 if [[ $dry == "0" ]]; then
   echo -e "${CYAN} Updating git tags...${RESET}"
-  if repoflow -i; then 
+  # Nobody is there to answer the push prompt, --yes pushes the new tag
+  if repoflow -i "$bump" --yes; then
     echo -e "${GREEN} Successfully updated the tags!${RESET}"
   else
     echo -e "${RED} Failed to update the tags successfully !${RESET}"
@@ -86,4 +92,19 @@ if [[ $dry == "0" ]]; then
   fi
 fi
 
+# ----------------------------
+# Step 6: Build the binary
+# ----------------------------
+echo -e "${CYAN}🛠 Building repoflow...${RESET}"
+# Built after the commit and tag in steps 4 and 5, so git describe gives the new tag rather than the one before it.
+# Building the package rather than main.go lets Go embed the commit
+version=$(git describe --tags --always --dirty)
+built=$(date -u +%Y-%m-%dT%H:%M:%SZ)
+if go build -ldflags "-X github.com/jonathon-chew/go-repoflow/internal/cli.buildVersion=${version} -X github.com/jonathon-chew/go-repoflow/internal/cli.buildDate=${built}" -o repoflow ./cmd/repoflow; then
+  echo -e "${GREEN} Built repoflow ${version}!${RESET}"
+else
+  echo -e "${RED} Build failed!${RESET}"
+  exit 1
+fi
+
 echo -e "${GREEN} CI pipeline completed successfully!${RESET}"